@@ -0,0 +1,429 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/edit"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// commands are the subcommands that can be given instead of the source and destination plans.
+var commands = map[string]func(args []string) error{
+	"config": subcommands("config", map[string]func(args []string) error{
+		"extract": configExtract,
+		"replace": configReplace,
+	}),
+	"deferred": subcommands("deferred", map[string]func(args []string) error{
+		"list":    deferredList,
+		"defer":   deferredDefer,
+		"undefer": deferredUndefer,
+	}),
+	"export-generated-config": exportGeneratedConfig,
+	"from-json":               fromJSON,
+	"force-replace": subcommands("force-replace", map[string]func(args []string) error{
+		"add":    forceReplaceAdd,
+		"remove": forceReplaceRemove,
+	}),
+	"imports": subcommands("imports", map[string]func(args []string) error{
+		"list":      importsList,
+		"set-id":    importsSetID,
+		"clear-id":  importsClearID,
+		"to-create": importsToCreate,
+	}),
+	"lineage": lineage,
+	"locks": subcommands("locks", map[string]func(args []string) error{
+		"list":        locksList,
+		"add-hash":    locksAddHash,
+		"remove-hash": locksRemoveHash,
+		"remove":      locksRemove,
+	}),
+	"metadata": metadata,
+	"sensitive": subcommands("sensitive", map[string]func(args []string) error{
+		"list":   sensitiveList,
+		"add":    sensitiveAdd,
+		"redact": sensitiveRedact,
+	}),
+	"targets": subcommands("targets", map[string]func(args []string) error{
+		"add":    targetsAdd,
+		"remove": targetsRemove,
+	}),
+}
+
+// subcommands returns a command that dispatches to the named subcommand.
+func subcommands(name string, cmds map[string]func(args []string) error) func(args []string) error {
+	return func(args []string) error {
+		if len(args) > 0 {
+			if cmd, ok := cmds[args[0]]; ok {
+				return cmd(args[1:])
+			}
+		}
+
+		names := []string{}
+		for n := range cmds {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return fmt.Errorf("terraform-plan-editor %s <%s>", name, strings.Join(names, "|"))
+	}
+}
+
+// parseArgs parses the flags and returns the positional arguments, which must match the usage. A
+// final usage that ends in "..." accepts one or more arguments. Any arguments that end in "-path"
+// or "-dir" are made absolute.
+func parseArgs(flags *flag.FlagSet, args []string, usage ...string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	variadic := len(usage) > 0 && strings.HasSuffix(usage[len(usage)-1], "...")
+	if flags.NArg() != len(usage) && (!variadic || flags.NArg() < len(usage)) {
+		return nil, fmt.Errorf("terraform-plan-editor %s <flags> <%s>", flags.Name(), strings.Join(usage, "> <"))
+	}
+
+	out := flags.Args()
+	for i, u := range usage {
+		if !strings.HasSuffix(u, "-path") && !strings.HasSuffix(u, "-dir") {
+			continue
+		}
+
+		var err error
+		out[i], err = filepath.Abs(out[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func configExtract(args []string) error {
+	flags := flag.NewFlagSet("config extract", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path", "dest-dir")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0]}).ExtractConfig(args[1])
+}
+
+func configReplace(args []string) error {
+	flags := flag.NewFlagSet("config replace", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "config-dir")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).ReplaceConfig(args[2])
+}
+
+func exportGeneratedConfig(args []string) error {
+	flags := flag.NewFlagSet("export-generated-config", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path", "dest-dir")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0]}).ExportGeneratedConfig(args[1])
+}
+
+func fromJSON(args []string) error {
+	flags := flag.NewFlagSet("from-json", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "json-plan-path", "dest-plan-path", "schema-path", "config-dir")
+	if err != nil {
+		return err
+	}
+
+	schemas, err := schema.Load(args[2])
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1], Schemas: schemas}).FromJSON(args[3])
+}
+
+func importsList(args []string) error {
+	flags := flag.NewFlagSet("imports list", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0]}).ReportImports(os.Stdout)
+}
+
+func importsSetID(args []string) error {
+	flags := flag.NewFlagSet("imports set-id", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address", "id")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).SetImportID(args[2], args[3])
+}
+
+func importsClearID(args []string) error {
+	flags := flag.NewFlagSet("imports clear-id", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).ClearImportID(args[2])
+}
+
+func importsToCreate(args []string) error {
+	flags := flag.NewFlagSet("imports to-create", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).ConvertImportToCreate(args[2])
+}
+
+func deferredList(args []string) error {
+	flags := flag.NewFlagSet("deferred list", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0]}).ReportDeferredChanges(os.Stdout)
+}
+
+func deferredDefer(args []string) error {
+	flags := flag.NewFlagSet("deferred defer", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address", "reason")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).DeferChange(args[2], args[3])
+}
+
+func deferredUndefer(args []string) error {
+	flags := flag.NewFlagSet("deferred undefer", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).UndeferChange(args[2])
+}
+
+func lineage(args []string) error {
+	flags := flag.NewFlagSet("lineage", flag.ExitOnError)
+	l := &edit.Lineage{}
+	flags.Func("lineage", "set the lineage, a lowercase UUID", func(s string) error {
+		l.Lineage = &s
+		return nil
+	})
+	newLineage := flags.Bool("new-lineage", false, "set a new random lineage")
+	flags.Func("serial", "set the serial", func(s string) error {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		l.Serial = &n
+		return nil
+	})
+
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path")
+	if err != nil {
+		return err
+	}
+
+	if *newLineage {
+		if l.Lineage != nil {
+			return errors.New("-lineage and -new-lineage cannot be used together")
+		}
+		s, err := state.NewLineage()
+		if err != nil {
+			return err
+		}
+		l.Lineage = &s
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).SetLineage(l)
+}
+
+func locksList(args []string) error {
+	flags := flag.NewFlagSet("locks list", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0]}).ReportLocks(os.Stdout)
+}
+
+func locksAddHash(args []string) error {
+	flags := flag.NewFlagSet("locks add-hash", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "provider", "hash...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).AddLockHashes(args[2], args[3:])
+}
+
+func locksRemoveHash(args []string) error {
+	flags := flag.NewFlagSet("locks remove-hash", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "provider", "hash...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).RemoveLockHashes(args[2], args[3:])
+}
+
+func locksRemove(args []string) error {
+	flags := flag.NewFlagSet("locks remove", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "provider")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).RemoveLock(args[2])
+}
+
+func metadata(args []string) error {
+	flags := flag.NewFlagSet("metadata", flag.ExitOnError)
+	m := &edit.Metadata{}
+	stringFlag := func(name string, usage string, v **string) {
+		flags.Func(name, usage, func(s string) error {
+			*v = &s
+			return nil
+		})
+	}
+	boolFlag := func(name string, usage string, v **bool) {
+		flags.Func(name, usage, func(s string) error {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			*v = &b
+			return nil
+		})
+	}
+	stringFlag("terraform-version", "set the terraform version, e.g. 1.9.1", &m.TerraformVersion)
+	stringFlag("timestamp", "set the RFC3339 timestamp of the plan, e.g. 2024-07-08T17:19:28Z", &m.Timestamp)
+	stringFlag("ui-mode", "set the plan mode, one of NORMAL, DESTROY or REFRESH_ONLY", &m.UIMode)
+	boolFlag("applyable", "set whether the plan is applyable", &m.Applyable)
+	boolFlag("complete", "set whether the plan is complete", &m.Complete)
+	boolFlag("errored", "set whether the plan errored", &m.Errored)
+
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).SetMetadata(m)
+}
+
+func targetsAdd(args []string) error {
+	flags := flag.NewFlagSet("targets add", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).AddTargets(args[2:])
+}
+
+func targetsRemove(args []string) error {
+	flags := flag.NewFlagSet("targets remove", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).RemoveTargets(args[2:])
+}
+
+func forceReplaceAdd(args []string) error {
+	flags := flag.NewFlagSet("force-replace add", flag.ExitOnError)
+	replace := flags.Bool("replace-action", false, "change the action of each instance to DELETE_THEN_CREATE with the REPLACE_BY_REQUEST reason")
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).AddForceReplace(args[2:], *replace)
+}
+
+func forceReplaceRemove(args []string) error {
+	flags := flag.NewFlagSet("force-replace remove", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "address...")
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1]}).RemoveForceReplace(args[2:])
+}
+
+func sensitiveList(args []string) error {
+	flags := flag.NewFlagSet("sensitive list", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "plan-path", "schema-path")
+	if err != nil {
+		return err
+	}
+
+	schemas, err := schema.Load(args[1])
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], Schemas: schemas}).ReportMissingSensitivePaths(os.Stdout)
+}
+
+func sensitiveAdd(args []string) error {
+	flags := flag.NewFlagSet("sensitive add", flag.ExitOnError)
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path", "schema-path")
+	if err != nil {
+		return err
+	}
+
+	schemas, err := schema.Load(args[2])
+	if err != nil {
+		return err
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1], Schemas: schemas}).AddSensitivePaths()
+}
+
+func sensitiveRedact(args []string) error {
+	flags := flag.NewFlagSet("sensitive redact", flag.ExitOnError)
+	schemaPath := flags.String("schema", "", "the output of 'terraform providers schema -json', to also redact the attributes it declares as sensitive")
+	selectors := []addrs.Selector{}
+	flags.Func("select", "only redact the resource instances the address or glob selects, can be repeated", func(s string) error {
+		selector, err := addrs.ParseSelector(s)
+		selectors = append(selectors, selector)
+
+		return err
+	})
+	args, err := parseArgs(flags, args, "source-plan-path", "dest-plan-path")
+	if err != nil {
+		return err
+	}
+
+	var schemas *schema.Schemas
+	if *schemaPath != "" {
+		schemas, err = schema.Load(*schemaPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return edit.New(&edit.Config{PlanPath: args[0], DstPath: args[1], Schemas: schemas, Selectors: selectors}).RedactSensitiveValues()
+}
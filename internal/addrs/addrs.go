@@ -0,0 +1,220 @@
+// Package addrs parses, formats and matches the string representations of Terraform addresses
+// that are found in plans, e.g. module.a["x"].module.b[0].aws_instance.web["k"] and
+// module.a.provider["registry.terraform.io/hashicorp/aws"].west.
+package addrs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InstanceKey is the key of a module or resource instance. A nil InstanceKey is used for
+// instances of modules and resources that don't use count or for_each.
+type InstanceKey interface {
+	String() string
+	instanceKey()
+}
+
+// IntKey is the key of an instance of a module or resource that uses count.
+type IntKey int
+
+func (k IntKey) String() string {
+	return fmt.Sprintf("[%d]", int(k))
+}
+
+func (IntKey) instanceKey() {}
+
+// StringKey is the key of an instance of a module or resource that uses for_each.
+type StringKey string
+
+func (k StringKey) String() string {
+	return "[" + quoteString(string(k)) + "]"
+}
+
+func (StringKey) instanceKey() {}
+
+// WildcardKey is the key of an instance of a module or resource whose keys are not yet known, as
+// found in the addresses of deferred changes.
+type WildcardKey struct{}
+
+func (WildcardKey) String() string {
+	return "[*]"
+}
+
+func (WildcardKey) instanceKey() {}
+
+// quoteString quotes the string using HCL's syntax, which also escapes template sequences.
+func quoteString(s string) string {
+	s = strconv.Quote(s)
+	s = strings.ReplaceAll(s, "${", "$${")
+
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
+
+// unquoteString reverses quoteString.
+func unquoteString(s string) (string, error) {
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return "", err
+	}
+	s = strings.ReplaceAll(s, "$${", "${")
+
+	return strings.ReplaceAll(s, "%%{", "%{"), nil
+}
+
+func keyString(k InstanceKey) string {
+	if k == nil {
+		return ""
+	}
+
+	return k.String()
+}
+
+// ModuleInstanceStep is a single module call in a module instance address.
+type ModuleInstanceStep struct {
+	Name string
+	Key  InstanceKey
+}
+
+func (s ModuleInstanceStep) String() string {
+	return "module." + s.Name + keyString(s.Key)
+}
+
+// ModuleInstance is the address of a module instance. The root module is an empty ModuleInstance.
+type ModuleInstance []ModuleInstanceStep
+
+func (m ModuleInstance) String() string {
+	steps := make([]string, len(m))
+	for i, s := range m {
+		steps[i] = s.String()
+	}
+
+	return strings.Join(steps, ".")
+}
+
+// ResourceMode is whether a resource is managed or a data source.
+type ResourceMode int
+
+const (
+	ManagedResourceMode ResourceMode = iota
+	DataResourceMode
+)
+
+// Resource is the address of a resource in a module.
+type Resource struct {
+	Mode ResourceMode
+	Type string
+	Name string
+}
+
+func (r Resource) String() string {
+	if r.Mode == DataResourceMode {
+		return "data." + r.Type + "." + r.Name
+	}
+
+	return r.Type + "." + r.Name
+}
+
+// Address is the address of a module instance, a resource, or a resource instance. The Resource
+// is nil for module instance addresses.
+type Address struct {
+	Module   ModuleInstance
+	Resource *Resource
+	Key      InstanceKey
+}
+
+// IsResourceInstance returns whether the address refers to a single resource instance.
+func (a Address) IsResourceInstance() bool {
+	if a.Resource == nil {
+		return false
+	}
+
+	return !hasWildcard(a.Module) && a.Key != (WildcardKey{})
+}
+
+// String returns the canonical representation of the address.
+func (a Address) String() string {
+	parts := []string{}
+	if len(a.Module) > 0 {
+		parts = append(parts, a.Module.String())
+	}
+	if a.Resource != nil {
+		parts = append(parts, a.Resource.String()+keyString(a.Key))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Equal returns whether the addresses are the same.
+func (a Address) Equal(other Address) bool {
+	return a.String() == other.String()
+}
+
+// TargetContains returns whether the address, used as a -target, contains the other address. This
+// follows the semantics of Terraform's targeting: the final module step or resource of a target
+// without an instance key contains all of its instances.
+func (a Address) TargetContains(other Address) bool {
+	if len(other.Module) < len(a.Module) {
+		return false
+	}
+
+	for i, step := range a.Module {
+		otherStep := other.Module[i]
+		if step.Name != otherStep.Name {
+			return false
+		}
+
+		if i == len(a.Module)-1 && a.Resource == nil && step.Key == nil {
+			continue
+		}
+
+		if keyString(step.Key) != keyString(otherStep.Key) {
+			return false
+		}
+	}
+
+	if a.Resource == nil {
+		return true
+	}
+
+	if other.Resource == nil || len(other.Module) != len(a.Module) || *a.Resource != *other.Resource {
+		return false
+	}
+
+	return a.Key == nil || keyString(a.Key) == keyString(other.Key)
+}
+
+// HasPrefix returns whether the module instance is, or is within, the prefix module instance. A
+// step of the prefix without a key, or with a wildcard key, matches every instance of the module.
+func (m ModuleInstance) HasPrefix(prefix ModuleInstance) bool {
+	if len(m) < len(prefix) {
+		return false
+	}
+
+	for i, step := range prefix {
+		if !step.contains(m[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s ModuleInstanceStep) contains(other ModuleInstanceStep) bool {
+	return s.Name == other.Name && keyContains(s.Key, other.Key)
+}
+
+func keyContains(k InstanceKey, other InstanceKey) bool {
+	return k == nil || k == (WildcardKey{}) || keyString(k) == keyString(other)
+}
+
+func hasWildcard(m ModuleInstance) bool {
+	for _, s := range m {
+		if s.Key == (WildcardKey{}) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package addrs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetContains(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		target   string
+		addr     string
+		contains bool
+	}{
+		"same instance":              {target: `aws_instance.web[0]`, addr: `aws_instance.web[0]`, contains: true},
+		"resource contains instance": {target: `aws_instance.web`, addr: `aws_instance.web["k"]`, contains: true},
+		"instance is not resource":   {target: `aws_instance.web[0]`, addr: `aws_instance.web`},
+		"other key":                  {target: `aws_instance.web[0]`, addr: `aws_instance.web[1]`},
+		"other mode":                 {target: `aws_instance.web`, addr: `data.aws_instance.web`},
+		"resource in module":         {target: `aws_instance.web`, addr: `module.a.aws_instance.web`},
+		"module contains instances":  {target: `module.a`, addr: `module.a[0].aws_instance.web`, contains: true},
+		"module contains nested":     {target: `module.a`, addr: `module.a.module.b[1].aws_instance.web`, contains: true},
+		"module instance":            {target: `module.a[0]`, addr: `module.a[0].aws_instance.web`, contains: true},
+		"other module instance":      {target: `module.a[0]`, addr: `module.a[1].aws_instance.web`},
+		"only final module step":     {target: `module.a.module.b`, addr: `module.a[0].module.b.aws_instance.web`},
+		"final module step":          {target: `module.a[0].module.b`, addr: `module.a[0].module.b[2].aws_instance.web`, contains: true},
+		"resource in module instance": {
+			target: `module.a.aws_instance.web`, addr: `module.a[0].aws_instance.web`,
+		},
+		"deferred wildcard": {target: `aws_instance.web`, addr: `aws_instance.web[*]`, contains: true},
+		"shorter address":   {target: `module.a.module.b`, addr: `module.a`},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := Parse(test.target)
+			require.NoError(t, err)
+			addr, err := Parse(test.addr)
+			require.NoError(t, err)
+			require.Equal(t, test.contains, target.TargetContains(addr))
+		})
+	}
+}
+
+func TestStringKeyQuoting(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{``, `"`, `\`, `${a}`, `%{if}`, `$${a}`, "\x00", `]`, `a.b`} {
+		addr := Address{Resource: &Resource{Type: "a", Name: "b"}, Key: StringKey(key)}
+		parsed, err := Parse(addr.String())
+		require.NoError(t, err, addr.String())
+		require.Equal(t, addr, parsed)
+	}
+}
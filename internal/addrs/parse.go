@@ -0,0 +1,212 @@
+package addrs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a module instance, resource, or resource instance address, e.g. module.a[0],
+// aws_instance.web or module.a["x"].data.aws_ami.ubuntu[1].
+func Parse(s string) (Address, error) {
+	p := &parser{s: s}
+	addr, err := p.address()
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+
+	return addr, nil
+}
+
+// ParseResourceInstance parses the address of a single resource instance.
+func ParseResourceInstance(s string) (Address, error) {
+	addr, err := Parse(s)
+	if err != nil {
+		return Address{}, err
+	}
+
+	if !addr.IsResourceInstance() {
+		return Address{}, fmt.Errorf("invalid address %q: must be a resource instance address", s)
+	}
+
+	return addr, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) address() (Address, error) {
+	addr := Address{}
+
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return Address{}, err
+		}
+
+		if name == "provider" {
+			return Address{}, errors.New("provider configurations are not resource or module addresses")
+		}
+
+		if name != "module" {
+			res, key, err := p.resource(name)
+			if err != nil {
+				return Address{}, err
+			}
+			addr.Resource = &res
+			addr.Key = key
+
+			break
+		}
+
+		if err := p.expect('.'); err != nil {
+			return Address{}, err
+		}
+		step := ModuleInstanceStep{}
+		if step.Name, err = p.ident(); err != nil {
+			return Address{}, err
+		}
+		if step.Key, err = p.key(); err != nil {
+			return Address{}, err
+		}
+		addr.Module = append(addr.Module, step)
+
+		if p.done() {
+			return addr, nil
+		}
+		if err := p.expect('.'); err != nil {
+			return Address{}, err
+		}
+	}
+
+	if !p.done() {
+		return Address{}, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos:], p.pos)
+	}
+
+	return addr, nil
+}
+
+func (p *parser) resource(first string) (Resource, InstanceKey, error) {
+	res := Resource{Mode: ManagedResourceMode, Type: first}
+
+	var err error
+	if first == "data" {
+		res.Mode = DataResourceMode
+		if err = p.expect('.'); err != nil {
+			return Resource{}, nil, err
+		}
+		if res.Type, err = p.ident(); err != nil {
+			return Resource{}, nil, err
+		}
+	}
+
+	if err = p.expect('.'); err != nil {
+		return Resource{}, nil, err
+	}
+	if res.Name, err = p.ident(); err != nil {
+		return Resource{}, nil, err
+	}
+
+	key, err := p.key()
+	if err != nil {
+		return Resource{}, nil, err
+	}
+
+	return res, key, nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) expect(c byte) error {
+	if p.done() {
+		return fmt.Errorf("expected %q at end of address", c)
+	}
+	if p.s[p.pos] != c {
+		return fmt.Errorf("expected %q at offset %d, found %q", c, p.pos, p.s[p.pos])
+	}
+	p.pos++
+
+	return nil
+}
+
+// ident parses an HCL identifier.
+func (p *parser) ident() (string, error) {
+	start := p.pos
+	for !p.done() {
+		c := p.s[p.pos]
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && (p.pos == start || (!isDigit && c != '-')) {
+			break
+		}
+		p.pos++
+	}
+
+	if p.pos == start {
+		if p.done() {
+			return "", fmt.Errorf("expected a name at end of address")
+		}
+		return "", fmt.Errorf("expected a name at offset %d, found %q", p.pos, p.s[p.pos])
+	}
+
+	return p.s[start:p.pos], nil
+}
+
+// quoted parses a quoted string.
+func (p *parser) quoted() (string, error) {
+	raw, err := strconv.QuotedPrefix(p.s[p.pos:])
+	if err != nil || !strings.HasPrefix(raw, `"`) {
+		return "", fmt.Errorf("expected a quoted string at offset %d", p.pos)
+	}
+
+	str, err := unquoteString(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted string at offset %d: %w", p.pos, err)
+	}
+	p.pos += len(raw)
+
+	return str, nil
+}
+
+// key parses an optional instance key, i.e. [0], ["x"] or [*].
+func (p *parser) key() (InstanceKey, error) {
+	if p.done() || p.s[p.pos] != '[' {
+		return nil, nil
+	}
+	p.pos++
+
+	var key InstanceKey
+	switch rest := p.s[p.pos:]; {
+	case strings.HasPrefix(rest, `"`):
+		str, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		key = StringKey(str)
+	case strings.HasPrefix(rest, "*"):
+		p.pos++
+		key = WildcardKey{}
+	default:
+		start := p.pos
+		for !p.done() && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		raw := p.s[start:p.pos]
+		i, err := strconv.Atoi(raw)
+		if err != nil || raw != strconv.Itoa(i) {
+			return nil, fmt.Errorf("invalid instance key at offset %d, must be a non-negative integer, a quoted string or *", start)
+		}
+		key = IntKey(i)
+	}
+
+	if err := p.expect(']'); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,112 @@
+package addrs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	web := &Resource{Mode: ManagedResourceMode, Type: "aws_instance", Name: "web"}
+
+	for addr, expected := range map[string]Address{
+		// Resource instances as found in the addr and prev_run_addr of resource changes.
+		`aws_instance.web`:      {Resource: web},
+		`aws_instance.web[0]`:   {Resource: web, Key: IntKey(0)},
+		`aws_instance.web[12]`:  {Resource: web, Key: IntKey(12)},
+		`aws_instance.web["k"]`: {Resource: web, Key: StringKey("k")},
+		`aws_instance.web["a.b]c[\"d\"]"]`: {
+			Resource: web, Key: StringKey(`a.b]c["d"]`),
+		},
+		`aws_instance.web["line\nbreak\ttab"]`: {Resource: web, Key: StringKey("line\nbreak\ttab")},
+		`aws_instance.web["$${not} %%{template}"]`: {
+			Resource: web, Key: StringKey("${not} %{template}"),
+		},
+		`aws_instance.web["ünïcode"]`: {Resource: web, Key: StringKey("ünïcode")},
+		`data.aws_ami.ubuntu`: {
+			Resource: &Resource{Mode: DataResourceMode, Type: "aws_ami", Name: "ubuntu"},
+		},
+		`data.aws_ami.ubuntu[1]`: {
+			Resource: &Resource{Mode: DataResourceMode, Type: "aws_ami", Name: "ubuntu"}, Key: IntKey(1),
+		},
+		`module.a["x"].module.b[0].aws_instance.web["k"]`: {
+			Module:   ModuleInstance{{Name: "a", Key: StringKey("x")}, {Name: "b", Key: IntKey(0)}},
+			Resource: web,
+			Key:      StringKey("k"),
+		},
+		`module.my-module.data.external.thing-1`: {
+			Module:   ModuleInstance{{Name: "my-module"}},
+			Resource: &Resource{Mode: DataResourceMode, Type: "external", Name: "thing-1"},
+		},
+		`module.data.data.data.data`: {
+			Module:   ModuleInstance{{Name: "data"}},
+			Resource: &Resource{Mode: DataResourceMode, Type: "data", Name: "data"},
+		},
+		// Partially expanded addresses as found in deferred changes.
+		`aws_instance.web[*]`: {Resource: web, Key: WildcardKey{}},
+		`module.a[*].aws_instance.web`: {
+			Module:   ModuleInstance{{Name: "a", Key: WildcardKey{}}},
+			Resource: web,
+		},
+		// Module instances as found in targets.
+		`module.a`:          {Module: ModuleInstance{{Name: "a"}}},
+		`module.a[0]`:       {Module: ModuleInstance{{Name: "a", Key: IntKey(0)}}},
+		`module.a.module.b`: {Module: ModuleInstance{{Name: "a"}, {Name: "b"}}},
+	} {
+		t.Run(addr, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := Parse(addr)
+			require.NoError(t, err)
+			require.Equal(t, expected, parsed)
+			require.Equal(t, addr, parsed.String())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for addr, err := range map[string]string{
+		``:                         `expected a name at end of address`,
+		`aws_instance`:             `expected '.' at end of address`,
+		`aws_instance.`:            `expected a name at end of address`,
+		`aws_instance.web.x`:       `unexpected ".x" at offset 16`,
+		`aws_instance.web[`:        `invalid instance key at offset 17`,
+		`aws_instance.web[0`:       `expected ']' at end of address`,
+		`aws_instance.web[-1]`:     `invalid instance key at offset 17`,
+		`aws_instance.web[01]`:     `invalid instance key at offset 17`,
+		`aws_instance.web[k]`:      `invalid instance key at offset 17`,
+		`aws_instance.web["k]`:     `expected a quoted string at offset 17`,
+		`aws_instance.web["k"][0]`: `unexpected "[0]" at offset 21`,
+		`aws_instance.web[0].x`:    `unexpected ".x" at offset 19`,
+		`aws_instance.0web`:        `expected a name at offset 13, found '0'`,
+		`module.a.`:                `expected a name at end of address`,
+		`module.a[0]module.b`:      `expected '.' at offset 11, found 'm'`,
+		`module`:                   `expected '.' at end of address`,
+		` aws_instance.web`:        `expected a name at offset 0, found ' '`,
+		`data.aws_ami`:             `expected '.' at end of address`,
+		`provider["registry.terraform.io/hashicorp/aws"]`: `provider configurations are not resource or module addresses`,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			t.Parallel()
+
+			_, e := Parse(addr)
+			require.ErrorContains(t, e, err)
+		})
+	}
+}
+
+func TestParseResourceInstance(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseResourceInstance(`module.a[0].aws_instance.web["k"]`)
+	require.NoError(t, err)
+
+	for _, addr := range []string{`module.a`, `aws_instance.web[*]`, `module.a[*].aws_instance.web`} {
+		_, err := ParseResourceInstance(addr)
+		require.ErrorContains(t, err, "must be a resource instance address")
+	}
+}
@@ -0,0 +1,156 @@
+package addrs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultProviderHostname is the hostname of providers whose source address doesn't include one.
+const DefaultProviderHostname = "registry.terraform.io"
+
+// DefaultProviderNamespace is the namespace of providers whose source address only has a type.
+const DefaultProviderNamespace = "hashicorp"
+
+// providerPartRe matches the namespace and type of a provider source address. The legacy "-"
+// namespace is used by providers in state written by Terraform 0.12.
+var providerPartRe = regexp.MustCompile(`^(-|[0-9a-z](?:[0-9a-z-]*[0-9a-z])?)$`)
+
+// Provider is the source address of a provider, e.g. registry.terraform.io/hashicorp/aws.
+type Provider struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+// ParseProvider parses a provider source address. The hostname and namespace are optional.
+func ParseProvider(s string) (Provider, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) > 3 {
+		return Provider{}, fmt.Errorf("invalid provider source %q: must be [hostname/][namespace/]type", s)
+	}
+
+	p := Provider{Hostname: DefaultProviderHostname, Namespace: DefaultProviderNamespace}
+	p.Type = strings.ToLower(parts[len(parts)-1])
+	if len(parts) > 1 {
+		p.Namespace = strings.ToLower(parts[len(parts)-2])
+	}
+	if len(parts) > 2 {
+		p.Hostname = strings.ToLower(parts[0])
+	}
+
+	if p.Hostname == "" || strings.ContainsAny(p.Hostname, " \t\"") {
+		return Provider{}, fmt.Errorf("invalid provider source %q: invalid hostname %q", s, p.Hostname)
+	}
+	if !providerPartRe.MatchString(p.Namespace) {
+		return Provider{}, fmt.Errorf("invalid provider source %q: invalid namespace %q", s, p.Namespace)
+	}
+	if p.Type == "-" || !providerPartRe.MatchString(p.Type) {
+		return Provider{}, fmt.Errorf("invalid provider source %q: invalid type %q", s, p.Type)
+	}
+
+	return p, nil
+}
+
+// String returns the fully qualified source address of the provider.
+func (p Provider) String() string {
+	return p.Hostname + "/" + p.Namespace + "/" + p.Type
+}
+
+// ProviderConfig is the address of a provider configuration in a module, as found in the provider
+// of a resource change, e.g. module.a.provider["registry.terraform.io/hashicorp/aws"].west.
+// Provider configurations belong to modules rather than module instances, so the module steps
+// never have keys.
+type ProviderConfig struct {
+	Module   ModuleInstance
+	Provider Provider
+	Alias    string
+}
+
+// ParseProviderConfig parses the address of a provider configuration.
+func ParseProviderConfig(s string) (ProviderConfig, error) {
+	p := &parser{s: s}
+	pc, err := p.providerConfig()
+	if err != nil {
+		return ProviderConfig{}, fmt.Errorf("invalid provider configuration address %q: %w", s, err)
+	}
+
+	return pc, nil
+}
+
+// String returns the canonical representation of the provider configuration address.
+func (pc ProviderConfig) String() string {
+	s := "provider[" + quoteString(pc.Provider.String()) + "]"
+	if pc.Alias != "" {
+		s += "." + pc.Alias
+	}
+	if len(pc.Module) > 0 {
+		s = pc.Module.String() + "." + s
+	}
+
+	return s
+}
+
+func (p *parser) providerConfig() (ProviderConfig, error) {
+	pc := ProviderConfig{}
+
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return ProviderConfig{}, err
+		}
+
+		if name == "provider" {
+			break
+		}
+		if name != "module" {
+			return ProviderConfig{}, fmt.Errorf("expected module or provider, found %q", name)
+		}
+
+		if err := p.expect('.'); err != nil {
+			return ProviderConfig{}, err
+		}
+		step := ModuleInstanceStep{}
+		if step.Name, err = p.ident(); err != nil {
+			return ProviderConfig{}, err
+		}
+		if !p.done() && p.s[p.pos] == '[' {
+			return ProviderConfig{}, errors.New("provider configurations cannot be in a module instance")
+		}
+		pc.Module = append(pc.Module, step)
+
+		if err := p.expect('.'); err != nil {
+			return ProviderConfig{}, err
+		}
+	}
+
+	if err := p.expect('['); err != nil {
+		return ProviderConfig{}, err
+	}
+	source, err := p.quoted()
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+	if pc.Provider, err = ParseProvider(source); err != nil {
+		return ProviderConfig{}, err
+	}
+	if err := p.expect(']'); err != nil {
+		return ProviderConfig{}, err
+	}
+
+	if !p.done() {
+		if err := p.expect('.'); err != nil {
+			return ProviderConfig{}, err
+		}
+		if pc.Alias, err = p.ident(); err != nil {
+			return ProviderConfig{}, err
+		}
+	}
+
+	if !p.done() {
+		return ProviderConfig{}, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos:], p.pos)
+	}
+
+	return pc, nil
+}
@@ -0,0 +1,84 @@
+package addrs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	aws := Provider{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "aws"}
+
+	for addr, expected := range map[string]ProviderConfig{
+		`provider["registry.terraform.io/hashicorp/aws"]`:      {Provider: aws},
+		`provider["registry.terraform.io/hashicorp/aws"].west`: {Provider: aws, Alias: "west"},
+		`module.a.module.b.provider["registry.terraform.io/hashicorp/aws"].west`: {
+			Module:   ModuleInstance{{Name: "a"}, {Name: "b"}},
+			Provider: aws,
+			Alias:    "west",
+		},
+		`provider["app.terraform.io/my-org/my-provider"]`: {
+			Provider: Provider{Hostname: "app.terraform.io", Namespace: "my-org", Type: "my-provider"},
+		},
+		`provider["registry.terraform.io/-/aws"]`: {
+			Provider: Provider{Hostname: "registry.terraform.io", Namespace: "-", Type: "aws"},
+		},
+		`provider["terraform.io/builtin/terraform"]`: {
+			Provider: Provider{Hostname: "terraform.io", Namespace: "builtin", Type: "terraform"},
+		},
+		`provider["localhost:8080/example/test"]`: {
+			Provider: Provider{Hostname: "localhost:8080", Namespace: "example", Type: "test"},
+		},
+	} {
+		t.Run(addr, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := ParseProviderConfig(addr)
+			require.NoError(t, err)
+			require.Equal(t, expected, parsed)
+			require.Equal(t, addr, parsed.String())
+		})
+	}
+}
+
+func TestParseProviderConfigCanonical(t *testing.T) {
+	t.Parallel()
+
+	for addr, canonical := range map[string]string{
+		`provider["aws"]`:                                 `provider["registry.terraform.io/hashicorp/aws"]`,
+		`provider["hashicorp/aws"].west`:                  `provider["registry.terraform.io/hashicorp/aws"].west`,
+		`provider["Registry.Terraform.io/HashiCorp/AWS"]`: `provider["registry.terraform.io/hashicorp/aws"]`,
+	} {
+		parsed, err := ParseProviderConfig(addr)
+		require.NoError(t, err)
+		require.Equal(t, canonical, parsed.String())
+	}
+}
+
+func TestParseProviderConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	for addr, err := range map[string]string{
+		`aws_instance.web`:            `expected module or provider, found "aws_instance"`,
+		`provider`:                    `expected '[' at end of address`,
+		`provider.aws`:                `expected '[' at offset 8, found '.'`,
+		`provider[aws]`:               `expected a quoted string at offset 9`,
+		`provider["a/b/c/d"]`:         `must be [hostname/][namespace/]type`,
+		`provider["hashicorp/-"]`:     `invalid type "-"`,
+		`provider["hashi_corp/aws"]`:  `invalid namespace "hashi_corp"`,
+		`provider["/hashicorp/aws"]`:  `invalid hostname ""`,
+		`provider["aws"].`:            `expected a name at end of address`,
+		`provider["aws"].west.east`:   `unexpected ".east" at offset 20`,
+		`module.a[0].provider["aws"]`: `provider configurations cannot be in a module instance`,
+		`module.a.aws_instance.web`:   `expected module or provider, found "aws_instance"`,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			t.Parallel()
+
+			_, e := ParseProviderConfig(addr)
+			require.ErrorContains(t, e, err)
+		})
+	}
+}
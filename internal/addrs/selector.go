@@ -0,0 +1,80 @@
+package addrs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Selector selects addresses either by containment or by glob. A selector that is a valid address
+// selects that address and everything within it, where a module step or resource without a key,
+// or with a wildcard key, selects every instance, e.g. module.a.aws_instance.web selects
+// module.a[0].aws_instance.web["x"]. Any other selector is a glob that is matched against the
+// canonical form of an address, where * matches any sequence of characters and ? matches any
+// single character, e.g. module.*.aws_instance.*.
+type Selector struct {
+	addr *Address
+	glob *regexp.Regexp
+	raw  string
+}
+
+// ParseSelector parses a selector.
+func ParseSelector(s string) (Selector, error) {
+	addr, err := Parse(s)
+	if err == nil {
+		return Selector{addr: &addr, raw: s}, nil
+	}
+
+	if !strings.ContainsAny(s, "*?") {
+		return Selector{}, fmt.Errorf("invalid selector, must be an address or a glob: %w", err)
+	}
+
+	pattern := regexp.QuoteMeta(s)
+	pattern = strings.ReplaceAll(pattern, `\*`, `.*`)
+	pattern = strings.ReplaceAll(pattern, `\?`, `.`)
+
+	return Selector{glob: regexp.MustCompile("^" + pattern + "$"), raw: s}, nil
+}
+
+func (s Selector) String() string {
+	return s.raw
+}
+
+// Match returns whether the selector selects the address.
+func (s Selector) Match(addr Address) bool {
+	if s.glob != nil {
+		return s.glob.MatchString(addr.String())
+	}
+
+	sel := s.addr
+	if !addr.Module.HasPrefix(sel.Module) {
+		return false
+	}
+	if sel.Resource == nil {
+		return true
+	}
+
+	return len(addr.Module) == len(sel.Module) &&
+		addr.Resource != nil && *addr.Resource == *sel.Resource &&
+		keyContains(sel.Key, addr.Key)
+}
+
+// MatchString parses the address and returns whether the selector selects it.
+func (s Selector) MatchString(addr string) (bool, error) {
+	a, err := Parse(addr)
+	if err != nil {
+		return false, err
+	}
+
+	return s.Match(a), nil
+}
+
+// MatchProviderConfig returns whether the selector selects the provider configuration. A glob is
+// matched against its canonical form and a module address selects the configurations in it.
+func (s Selector) MatchProviderConfig(pc ProviderConfig) bool {
+	if s.glob != nil {
+		return s.glob.MatchString(pc.String())
+	}
+
+	return s.addr.Resource == nil && pc.Module.HasPrefix(s.addr.Module)
+}
@@ -0,0 +1,98 @@
+package addrs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatch(t *testing.T) {
+	t.Parallel()
+
+	addrs := []string{
+		`aws_instance.web`,
+		`aws_instance.web[0]`,
+		`aws_instance.web["k"]`,
+		`data.aws_instance.web`,
+		`module.a.aws_instance.web`,
+		`module.a[0].aws_instance.web[1]`,
+		`module.a["x"].module.b[0].aws_instance.web["k"]`,
+		`module.b.aws_s3_bucket.logs`,
+	}
+
+	for selector, expected := range map[string][]string{
+		`aws_instance.web`:    {`aws_instance.web`, `aws_instance.web[0]`, `aws_instance.web["k"]`},
+		`aws_instance.web[*]`: {`aws_instance.web`, `aws_instance.web[0]`, `aws_instance.web["k"]`},
+		`aws_instance.web[0]`: {`aws_instance.web[0]`},
+		`module.a`: {
+			`module.a.aws_instance.web`,
+			`module.a[0].aws_instance.web[1]`,
+			`module.a["x"].module.b[0].aws_instance.web["k"]`,
+		},
+		`module.a["x"]`:                {`module.a["x"].module.b[0].aws_instance.web["k"]`},
+		`module.a.module.b`:            {`module.a["x"].module.b[0].aws_instance.web["k"]`},
+		`module.a[0].aws_instance.web`: {`module.a[0].aws_instance.web[1]`},
+		`module.a.aws_instance.web`:    {`module.a.aws_instance.web`, `module.a[0].aws_instance.web[1]`},
+		`data.aws_instance.web`:        {`data.aws_instance.web`},
+		`*`:                            addrs,
+		`*.aws_instance.*`:             {`data.aws_instance.web`, `module.a.aws_instance.web`, `module.a[0].aws_instance.web[1]`, `module.a["x"].module.b[0].aws_instance.web["k"]`},
+		`aws_*`:                        {`aws_instance.web`, `aws_instance.web[0]`, `aws_instance.web["k"]`},
+		`module.?.*`:                   {`module.a.aws_instance.web`, `module.b.aws_s3_bucket.logs`},
+		`aws_instance.web[?]`:          {`aws_instance.web[0]`},
+		`*["k"]`:                       {`aws_instance.web["k"]`, `module.a["x"].module.b[0].aws_instance.web["k"]`},
+		`module.b`:                     {`module.b.aws_s3_bucket.logs`},
+		`module.c`:                     {},
+	} {
+		t.Run(selector, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := ParseSelector(selector)
+			require.NoError(t, err)
+
+			matched := []string{}
+			for _, addr := range addrs {
+				ok, err := s.MatchString(addr)
+				require.NoError(t, err)
+				if ok {
+					matched = append(matched, addr)
+				}
+			}
+			require.Equal(t, expected, matched)
+		})
+	}
+}
+
+func TestSelectorMatchProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	pc, err := ParseProviderConfig(`module.a.module.b.provider["registry.terraform.io/hashicorp/aws"].west`)
+	require.NoError(t, err)
+
+	for selector, expected := range map[string]bool{
+		`module.a`:             true,
+		`module.a.module.b`:    true,
+		`module.b`:             false,
+		`module.a.aws_x.y`:     false,
+		`*hashicorp/aws"]*`:    true,
+		`*.west`:               true,
+		`*.east`:               false,
+		`provider*`:            false,
+		`module.a.*.provider*`: true,
+	} {
+		s, err := ParseSelector(selector)
+		require.NoError(t, err)
+		require.Equal(t, expected, s.MatchProviderConfig(pc), selector)
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseSelector(`aws_instance`)
+	require.ErrorContains(t, err, "invalid selector, must be an address or a glob")
+
+	s, err := ParseSelector(`aws_*`)
+	require.NoError(t, err)
+	_, err = s.MatchString(`aws_instance`)
+	require.ErrorContains(t, err, `invalid address "aws_instance"`)
+}
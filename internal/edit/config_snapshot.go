@@ -0,0 +1,266 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// configSnapshotDir is the directory of a plan that holds the snapshot of the configuration it was
+// created from. Each module's files are in a directory named "m-" followed by the module key, and
+// modules.json lists the modules.
+const configSnapshotDir = "tfconfig"
+
+// configSnapshotModule is a module in the manifest of a config snapshot, or in the manifest that
+// `terraform init` writes to .terraform/modules/modules.json.
+type configSnapshotModule struct {
+	Key     string `json:"Key"`
+	Source  string `json:"Source,omitempty"`
+	Version string `json:"Version,omitempty"`
+	Dir     string `json:"Dir"`
+}
+
+// writeConfigSnapshot writes the configuration in the source directory to the config snapshot of
+// the unpacked plan in planDir. The source is either a snapshot that has already been extracted,
+// which has a modules.json manifest, or a root module. Child modules of a root module are found
+// with the manifest written by `terraform init`, if there is one.
+func writeConfigSnapshot(src string, planDir string) error {
+	dst := filepath.Join(planDir, configSnapshotDir)
+
+	if _, err := os.Stat(filepath.Join(src, "modules.json")); err == nil {
+		return copyDir(src, dst)
+	}
+
+	modules := []configSnapshotModule{{Key: "", Dir: "."}}
+	b, err := os.ReadFile(filepath.Join(src, ".terraform", "modules", "modules.json"))
+	switch {
+	case err == nil:
+		manifest := struct {
+			Modules []configSnapshotModule `json:"Modules"`
+		}{}
+		if err = json.Unmarshal(b, &manifest); err != nil {
+			return fmt.Errorf("unable to decode module manifest: %w", err)
+		}
+		modules = manifest.Modules
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	for _, m := range modules {
+		entries, err := os.ReadDir(filepath.Join(src, m.Dir))
+		if err != nil {
+			return fmt.Errorf("unable to read module %q: %w", m.Key, err)
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || (!strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json")) {
+				continue
+			}
+
+			if err = copyFile(filepath.Join(src, m.Dir, name), filepath.Join(dst, "m-"+m.Key, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	b, err = json.Marshal(modules)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dst, "modules.json"), b, 0o644)
+}
+
+// readConfigSnapshotManifest reads the modules.json manifest of the config snapshot of the
+// unpacked plan in planDir.
+func readConfigSnapshotManifest(planDir string) ([]configSnapshotModule, error) {
+	b, err := os.ReadFile(filepath.Join(planDir, configSnapshotDir, "modules.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []configSnapshotModule{}
+	if err = json.Unmarshal(b, &modules); err != nil {
+		return nil, fmt.Errorf("unable to decode %s/modules.json: %w", configSnapshotDir, err)
+	}
+
+	return modules, nil
+}
+
+// ExtractConfig writes the config snapshot of the plan to dir as a module tree, with each module in
+// the directory it was in when the plan was created. When there are child modules, the manifest
+// that `terraform init` writes to .terraform/modules/modules.json is written too, so that the tree
+// can be put back with ReplaceConfig.
+func (e *Editor) ExtractConfig(dir string) error {
+	planDir, err := e.unzipPlan()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(planDir)
+
+	modules, err := readConfigSnapshotManifest(planDir)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range modules {
+		if !filepath.IsLocal(filepath.FromSlash(m.Dir)) {
+			return fmt.Errorf("module %q is in %s, which is outside of the root module and can't be extracted", m.Key, m.Dir)
+		}
+
+		src := filepath.Join(planDir, configSnapshotDir, "m-"+m.Key)
+		dst := filepath.Join(dir, filepath.FromSlash(m.Dir))
+		if _, err := os.Stat(src); err != nil {
+			if m.Key == "" {
+				return errors.New("the root module has no files in the config snapshot")
+			}
+
+			// Terraform doesn't snapshot child modules without configuration files, so their
+			// directory is written empty.
+			fmt.Printf("warning: module %q has no files in the config snapshot\n", m.Key)
+			fmt.Println("write: " + dst)
+			if err = os.MkdirAll(dst, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Println("write: " + dst)
+		if err = copyDir(src, dst); err != nil {
+			return err
+		}
+	}
+
+	if len(modules) <= 1 {
+		return nil
+	}
+
+	b, err := json.Marshal(struct {
+		Modules []configSnapshotModule `json:"Modules"`
+	}{modules})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, ".terraform", "modules", "modules.json")
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	fmt.Println("write: " + path)
+
+	return os.WriteFile(path, b, 0o644)
+}
+
+// ReplaceConfig replaces the config snapshot of the plan with the configuration in dir, which is
+// either a root module or a tree written by ExtractConfig.
+func (e *Editor) ReplaceConfig(dir string) error {
+	return e.updatePlanDir(func(planDir string, p *plan.Plan) error {
+		if err := os.RemoveAll(filepath.Join(planDir, configSnapshotDir)); err != nil {
+			return err
+		}
+
+		if err := writeConfigSnapshot(dir, planDir); err != nil {
+			return err
+		}
+
+		return checkConfigSnapshot(planDir, p)
+	})
+}
+
+// checkConfigSnapshot returns an error when the root module in the config snapshot of the unpacked
+// plan in planDir has no files, and for each resource change in a module that isn't in the
+// snapshot. Child modules without files are only warned about, as a module may have no
+// configuration files of its own. Deletes are not checked, as the module of an object that's being deleted may have
+// been removed from the configuration. Plans without a config snapshot are not checked.
+func checkConfigSnapshot(planDir string, p *plan.Plan) error {
+	modules, err := readConfigSnapshotManifest(planDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	keys := map[string]bool{}
+	for _, m := range modules {
+		keys[m.Key] = true
+
+		entries, err := os.ReadDir(filepath.Join(planDir, configSnapshotDir, "m-"+m.Key))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		switch {
+		case len(entries) > 0:
+		case m.Key == "":
+			errs = append(errs, fmt.Errorf("%s: the root module has no files", configSnapshotDir))
+		default:
+			fmt.Printf("warning: %s: module %q has no files\n", configSnapshotDir, m.Key)
+		}
+	}
+
+	changes := append([]*plan.ResourceInstanceChange{}, p.GetResourceChanges()...)
+	for _, d := range p.GetDeferredChanges() {
+		changes = append(changes, d.GetChange())
+	}
+
+	for _, c := range changes {
+		switch c.GetChange().GetAction() {
+		case plan.Action_DELETE, plan.Action_FORGET:
+			continue
+		}
+
+		a, err := addrs.Parse(c.GetAddr())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		names := []string{}
+		for _, step := range a.Module {
+			names = append(names, step.Name)
+		}
+		if key := strings.Join(names, "."); !keys[key] {
+			errs = append(errs, fmt.Errorf("%s: module %q is not in the config snapshot", resourceInstanceChangeKey(c), key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// copyDir copies every file in the source directory tree to the destination.
+func copyDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		return copyFile(path, filepath.Join(dst, rel))
+	})
+}
+
+func copyFile(src string, dst string) error {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, b, 0o644)
+}
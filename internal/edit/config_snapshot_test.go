@@ -0,0 +1,178 @@
+package edit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+const testConfigSnapshotModules = `[{"Key":"","Dir":"."},{"Key":"db","Source":"registry.terraform.io/acme/db/aws","Version":"1.0.0","Dir":".terraform/modules/db"},{"Key":"net","Source":"./net","Dir":"net"}]`
+
+func testConfigSnapshotPlan(addrs ...string) *plan.Plan {
+	p := &plan.Plan{Version: 3, TerraformVersion: "1.9.1"}
+	for _, addr := range addrs {
+		p.ResourceChanges = append(p.ResourceChanges, &plan.ResourceInstanceChange{
+			Addr:   addr,
+			Change: &plan.Change{Action: plan.Action_CREATE},
+		})
+	}
+
+	return p
+}
+
+func requireConfigSnapshotPlan(t *testing.T, p *plan.Plan, modules string) string {
+	t.Helper()
+
+	return requirePlanFile(t, p, map[string]string{
+		"tfconfig/modules.json": modules,
+		"tfconfig/m-/main.tf":   "# main\n",
+		"tfconfig/m-db/db.tf":   "# db\n",
+		"tfconfig/m-net/net.tf": "# net\n",
+	})
+}
+
+func TestExtractAndReplaceConfig(t *testing.T) {
+	t.Parallel()
+
+	p := testConfigSnapshotPlan("aws_instance.web", "module.net[0].aws_vpc.main", "module.db.aws_db_instance.main")
+	planPath := requireConfigSnapshotPlan(t, p, testConfigSnapshotModules)
+	dir := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, New(&Config{PlanPath: planPath}).ExtractConfig(dir))
+
+	for name, content := range map[string]string{
+		"main.tf":                     "# main\n",
+		"net/net.tf":                  "# net\n",
+		".terraform/modules/db/db.tf": "# db\n",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, content, string(b))
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, ".terraform", "modules", "modules.json"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Modules":`+testConfigSnapshotModules+`}`, string(b))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "net.tf"), []byte("# edited\n"), 0o644))
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).ReplaceConfig(dir))
+
+	files := requireZipFiles(t, dstPath)
+	require.Equal(t, "# main\n", files["tfconfig/m-/main.tf"])
+	require.Equal(t, "# db\n", files["tfconfig/m-db/db.tf"])
+	require.Equal(t, "# edited\n", files["tfconfig/m-net/net.tf"])
+	require.JSONEq(t, testConfigSnapshotModules, files["tfconfig/modules.json"])
+}
+
+func TestReplaceConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		p      *plan.Plan
+		remove string
+		err    string
+	}{
+		"root module without files": {
+			p:      testConfigSnapshotPlan("module.net.aws_vpc.main"),
+			remove: "main.tf",
+			err:    `tfconfig: the root module has no files`,
+		},
+		"child module without files": {
+			p:      testConfigSnapshotPlan("aws_instance.web"),
+			remove: "net/net.tf",
+		},
+		"resource in missing module": {
+			p:   testConfigSnapshotPlan("module.gone.aws_instance.web"),
+			err: `module.gone.aws_instance.web: module "gone" is not in the config snapshot`,
+		},
+		"delete in removed module": {
+			p: &plan.Plan{
+				Version:          3,
+				TerraformVersion: "1.9.1",
+				ResourceChanges: []*plan.ResourceInstanceChange{
+					{
+						Addr:         "module.gone.aws_instance.web",
+						Change:       &plan.Change{Action: plan.Action_DELETE},
+						ActionReason: plan.ResourceInstanceActionReason_DELETE_BECAUSE_NO_MODULE,
+					},
+				},
+			},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			planPath := requireConfigSnapshotPlan(t, test.p, testConfigSnapshotModules)
+			dir := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, New(&Config{PlanPath: planPath}).ExtractConfig(dir))
+			if test.remove != "" {
+				require.NoError(t, os.Remove(filepath.Join(dir, test.remove)))
+			}
+
+			dstPath := filepath.Join(t.TempDir(), "edited.plan")
+			err := New(&Config{PlanPath: planPath, DstPath: dstPath}).ReplaceConfig(dir)
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.err)
+			require.NoFileExists(t, dstPath)
+		})
+	}
+}
+
+func TestExtractConfigOutsideRoot(t *testing.T) {
+	t.Parallel()
+
+	modules := []configSnapshotModule{{Key: "", Dir: "."}, {Key: "net", Source: "../net", Dir: "../net"}}
+	b, err := json.Marshal(modules)
+	require.NoError(t, err)
+
+	planPath := requireConfigSnapshotPlan(t, testConfigSnapshotPlan(), string(b))
+	err = New(&Config{PlanPath: planPath}).ExtractConfig(t.TempDir())
+	require.EqualError(t, err, `module "net" is in ../net, which is outside of the root module and can't be extracted`)
+}
+
+func TestExtractConfigWithoutFiles(t *testing.T) {
+	t.Parallel()
+
+	// Terraform doesn't snapshot child modules without configuration files.
+	planPath := requirePlanFile(t, testConfigSnapshotPlan("aws_instance.web"), map[string]string{
+		"tfconfig/modules.json": testConfigSnapshotModules,
+		"tfconfig/m-/main.tf":   "# main\n",
+		"tfconfig/m-db/db.tf":   "# db\n",
+	})
+	dir := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, New(&Config{PlanPath: planPath}).ExtractConfig(dir))
+	require.DirExists(t, filepath.Join(dir, "net"))
+
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).ReplaceConfig(dir))
+
+	planPath = requirePlanFile(t, testConfigSnapshotPlan(), map[string]string{
+		"tfconfig/modules.json": testConfigSnapshotModules,
+		"tfconfig/m-net/net.tf": "# net\n",
+	})
+	err := New(&Config{PlanPath: planPath}).ExtractConfig(t.TempDir())
+	require.EqualError(t, err, "the root module has no files in the config snapshot")
+}
+
+func TestEditChecksConfigSnapshot(t *testing.T) {
+	t.Parallel()
+
+	planPath := requireConfigSnapshotPlan(t, testConfigSnapshotPlan("module.net.aws_vpc.main"), testConfigSnapshotModules)
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	err := New(&Config{
+		PlanPath:      planPath,
+		DstPath:       dstPath,
+		TextEditorCmd: sedEditor(t, `s/"net"/"network"/`),
+		Format:        FormatJSON,
+	}).Edit()
+	require.ErrorContains(t, err, `module.net.aws_vpc.main: module "net" is not in the config snapshot`)
+	require.NoFileExists(t, dstPath)
+}
@@ -0,0 +1,129 @@
+package edit
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// checkPlanStates returns a warning for each disagreement between the tfplan and the state
+// snapshots of the plan unpacked in dir. Terraform plans with the refreshed state in tfstate and
+// the state of the previous run in tfstate-prev, so the before value of each resource instance
+// change should match the instance's attributes in tfstate, and each drift should go from the
+// attributes in tfstate-prev to those in tfstate. Plans can disagree with their snapshots before
+// they're edited, so these are only warnings. Snapshots with different lineages are an error.
+// Snapshots that the plan doesn't have are not checked.
+func checkPlanStates(schemas *schema.Schemas, dir string, p *plan.Plan) ([]string, error) {
+	states, err := loadStateFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := map[string]map[string]*state.Instance{}
+	for name, s := range states {
+		instances[name] = map[string]*state.Instance{}
+		for _, r := range s.Resources {
+			for _, i := range r.Instances {
+				key, err := stateInstanceKey(r, i)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				instances[name][key] = i
+			}
+		}
+	}
+
+	prior, prev := states["tfstate"], states["tfstate-prev"]
+	if prior != nil && prev != nil && prior.Lineage != prev.Lineage {
+		return nil, fmt.Errorf("tfstate has lineage %q but tfstate-prev has lineage %q", prior.Lineage, prev.Lineage)
+	}
+
+	warnings := []string{}
+
+	for _, d := range dynamicValues(p) {
+		if d.resource == nil {
+			continue
+		}
+
+		var name string
+		switch {
+		case d.keys[0] == "resource_changes" && d.keys[2] == "before":
+			name = "tfstate"
+		case d.keys[0] == "resource_drift" && d.keys[2] == "before":
+			name = "tfstate-prev"
+		case d.keys[0] == "resource_drift" && d.keys[2] == "after":
+			name = "tfstate"
+		default:
+			continue
+		}
+		if states[name] == nil {
+			continue
+		}
+
+		key := resourceInstanceChangeKey(d.resource)
+		if err = checkStateInstance(schemas, d, name, instances[name][key]); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	return warnings, nil
+}
+
+// checkStateInstance checks that the value matches the attributes of the instance in the named
+// state snapshot. A null value, such as the after value of a resource that was deleted outside
+// of Terraform, is not checked.
+func checkStateInstance(schemas *schema.Schemas, d dynamicValue, name string, i *state.Instance) error {
+	ty := dynamicValueSchemaType(schemas, d)
+	if ty == cty.NilType {
+		var err error
+		if ty, err = impliedType(d.value.GetMsgpack()); err != nil {
+			return pathError(d.key(), err)
+		}
+	}
+
+	val, err := ctymsgpack.Unmarshal(d.value.GetMsgpack(), ty)
+	if err != nil {
+		return pathError(d.key(), err)
+	}
+	if val.IsNull() {
+		return nil
+	}
+
+	if i == nil || len(i.Attributes) == 0 {
+		return fmt.Errorf("%s: %s has no attributes for the instance", d.key(), name)
+	}
+
+	attrs, err := ctyjson.Unmarshal(i.Attributes, ty)
+	if err != nil {
+		return pathError(d.key(), fmt.Errorf("does not match the instance's attributes in %s: %w", name, err))
+	}
+
+	if eq := val.Equals(attrs); eq.IsKnown() && eq.True() {
+		return nil
+	}
+
+	differ := ""
+	if val.Type().IsObjectType() && attrs.Type().IsObjectType() {
+		for _, attr := range mapkeys.Sorted(val.Type().AttributeTypes()) {
+			if !attrs.Type().HasAttribute(attr) {
+				differ += " " + formatCtyPath(cty.GetAttrPath(attr))
+				continue
+			}
+			if eq := val.GetAttr(attr).Equals(attrs.GetAttr(attr)); !eq.IsKnown() || eq.False() {
+				differ += " " + formatCtyPath(cty.GetAttrPath(attr))
+			}
+		}
+	}
+	if differ != "" {
+		differ = ", differing at" + differ
+	}
+
+	return fmt.Errorf("%s: does not match the instance's attributes in %s%s", d.key(), name, differ)
+}
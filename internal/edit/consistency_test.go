@@ -0,0 +1,156 @@
+package edit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func testConsistencyPlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	value := func(password string) *plan.DynamicValue {
+		user := func(name string, token string) cty.Value {
+			return cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name), "token": cty.StringVal(token)})
+		}
+		val := cty.ObjectVal(map[string]cty.Value{
+			"name":     cty.StringVal("db"),
+			"password": cty.StringVal(password),
+			"user":     cty.ListVal([]cty.Value{user("admin", "t0"), user("app", "t1")}),
+		})
+
+		return requireDynamicValue(t, val, val.Type())
+	}
+
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_db_instance.main",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action: plan.Action_UPDATE,
+					Values: []*plan.DynamicValue{value("hunter2"), value("changeme")},
+				},
+			},
+		},
+		ResourceDrift: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_db_instance.main",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action: plan.Action_UPDATE,
+					Values: []*plan.DynamicValue{value("drifted"), value("hunter2")},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckPlanStates(t *testing.T) {
+	t.Parallel()
+
+	prevStateFile := strings.Replace(testStateFile, `"password": "hunter2"`, `"password": "drifted"`, 1)
+	for desc, test := range map[string]struct {
+		addr     string
+		files    map[string]string
+		warnings []string
+		err      string
+	}{
+		"consistent": {
+			files: map[string]string{"tfstate": testStateFile, "tfstate-prev": prevStateFile},
+		},
+		"no states": {},
+		// Plans quote string keys the way Terraform does, escaping template sequences.
+		"string key": {
+			addr: `aws_db_instance.main["$${x}"]`,
+			files: map[string]string{
+				"tfstate":      strings.Replace(testStateFile, `"schema_version": 0,`, `"index_key": "${x}", "schema_version": 0,`, 1),
+				"tfstate-prev": strings.Replace(prevStateFile, `"schema_version": 0,`, `"index_key": "${x}", "schema_version": 0,`, 1),
+			},
+		},
+		"changed before": {
+			files: map[string]string{"tfstate": strings.Replace(testStateFile, `"t1"`, `"t2"`, 1)},
+			warnings: []string{
+				`resource_changes["aws_db_instance.main"].before: does not match the instance's attributes in tfstate, differing at .user`,
+				`resource_drift["aws_db_instance.main"].after: does not match the instance's attributes in tfstate, differing at .user`,
+			},
+		},
+		"drift": {
+			files:    map[string]string{"tfstate": testStateFile, "tfstate-prev": testStateFile},
+			warnings: []string{`resource_drift["aws_db_instance.main"].before: does not match the instance's attributes in tfstate-prev, differing at .password`},
+		},
+		"missing instance": {
+			files:    map[string]string{"tfstate-prev": strings.Replace(prevStateFile, `"name": "main"`, `"name": "other"`, 1)},
+			warnings: []string{`resource_drift["aws_db_instance.main"].before: tfstate-prev has no attributes for the instance`},
+		},
+		"lineage": {
+			files: map[string]string{
+				"tfstate":      testStateFile,
+				"tfstate-prev": strings.Replace(prevStateFile, "0b7b5e8c", "1b7b5e8c", 1),
+			},
+			err: `tfstate has lineage "0b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a" but tfstate-prev has lineage "1b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a"`,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := testConsistencyPlan(t)
+			if test.addr != "" {
+				p.ResourceChanges[0].Addr = test.addr
+				p.ResourceDrift[0].Addr = test.addr
+			}
+
+			dir := t.TempDir()
+			for name, contents := range test.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+			}
+
+			warnings, err := checkPlanStates(nil, dir, p)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			if test.warnings == nil {
+				test.warnings = []string{}
+			}
+			require.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestEditPlanStatesMismatch(t *testing.T) {
+	t.Parallel()
+
+	// Mismatches are only warnings, so a plan that already disagrees with its snapshots can be
+	// edited.
+	files := map[string]string{"tfstate": strings.Replace(testStateFile, `"t1"`, `"t2"`, 1)}
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	require.NoError(t, New(&Config{
+		PlanPath:      requirePlanFile(t, testConsistencyPlan(t), files),
+		DstPath:       dstPath,
+		TextEditorCmd: "true",
+		Format:        FormatJSON,
+	}).Edit())
+	require.FileExists(t, dstPath)
+
+	// A lineage mismatch is still refused before the plan is written.
+	files["tfstate-prev"] = strings.Replace(testStateFile, "0b7b5e8c", "1b7b5e8c", 1)
+	dstPath = filepath.Join(t.TempDir(), "edited.plan")
+	err := New(&Config{
+		PlanPath:      requirePlanFile(t, testConsistencyPlan(t), files),
+		DstPath:       dstPath,
+		TextEditorCmd: "true",
+		Format:        FormatJSON,
+	}).Edit()
+	require.ErrorContains(t, err, "but tfstate-prev has lineage")
+	require.NoFileExists(t, dstPath)
+}
@@ -0,0 +1,130 @@
+package edit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// parseDeferredReason parses the name of a DeferredReason, e.g. PROVIDER_CONFIG_UNKNOWN.
+func parseDeferredReason(name string) (plan.DeferredReason, error) {
+	reason, ok := plan.DeferredReason_value[strings.ToUpper(name)]
+	if !ok || reason == int32(plan.DeferredReason_INVALID) {
+		names := []string{}
+		for n, v := range plan.DeferredReason_value {
+			if v != int32(plan.DeferredReason_INVALID) {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+
+		return plan.DeferredReason_INVALID, fmt.Errorf("invalid deferred reason %q, must be one of %s", name, strings.Join(names, ", "))
+	}
+
+	return plan.DeferredReason(reason), nil
+}
+
+// ReportDeferredChanges writes a report of every deferred change in the plan, grouped by the
+// reason it was deferred.
+func (e *Editor) ReportDeferredChanges(w io.Writer) error {
+	p, err := e.readPlan()
+	if err != nil {
+		return err
+	}
+
+	byReason := map[plan.DeferredReason][]*plan.ResourceInstanceChange{}
+	for _, d := range p.GetDeferredChanges() {
+		reason := d.GetDeferred().GetReason()
+		byReason[reason] = append(byReason[reason], d.GetChange())
+	}
+
+	reasons := []plan.DeferredReason{}
+	for reason := range byReason {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return reasons[i] < reasons[j] })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, reason := range reasons {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s (%d)\n", reason, len(byReason[reason]))
+		for _, c := range byReason[reason] {
+			fmt.Fprintf(tw, "  %s\t%s\n", resourceInstanceChangeKey(c), c.GetChange().GetAction())
+		}
+	}
+
+	return tw.Flush()
+}
+
+// DeferChange moves every change for the resource instance at the address from resource_changes
+// to deferred_changes with the given reason.
+func (e *Editor) DeferChange(addr string, reasonName string) error {
+	reason, err := parseDeferredReason(reasonName)
+	if err != nil {
+		return err
+	}
+
+	return e.updatePlan(func(p *plan.Plan) error {
+		changes := []*plan.ResourceInstanceChange{}
+		moved := false
+		for _, c := range p.GetResourceChanges() {
+			if c.GetAddr() != addr {
+				changes = append(changes, c)
+				continue
+			}
+
+			p.DeferredChanges = append(p.DeferredChanges, &plan.DeferredResourceInstanceChange{
+				Deferred: &plan.Deferred{Reason: reason},
+				Change:   c,
+			})
+			moved = true
+		}
+
+		if !moved {
+			return fmt.Errorf("no resource change found for %s", addr)
+		}
+
+		// A plan with deferred changes does not include every planned action.
+		p.ResourceChanges = changes
+		p.Complete = false
+
+		return nil
+	})
+}
+
+// UndeferChange moves every change for the resource instance at the address from
+// deferred_changes back to resource_changes. The plan is complete again once nothing is deferred.
+func (e *Editor) UndeferChange(addr string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		deferred := []*plan.DeferredResourceInstanceChange{}
+		moved := false
+		for _, d := range p.GetDeferredChanges() {
+			if d.GetChange().GetAddr() != addr {
+				deferred = append(deferred, d)
+				continue
+			}
+
+			p.ResourceChanges = append(p.ResourceChanges, d.GetChange())
+			moved = true
+		}
+
+		if !moved {
+			return fmt.Errorf("no deferred change found for %s", addr)
+		}
+
+		p.DeferredChanges = deferred
+
+		// Terraform only plans incompletely when it defers changes or plans with targets.
+		if len(p.GetDeferredChanges()) == 0 && len(p.GetDeferredActionInvocations()) == 0 && len(p.GetTargetAddrs()) == 0 {
+			p.Complete = true
+		}
+
+		return nil
+	})
+}
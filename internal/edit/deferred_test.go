@@ -0,0 +1,157 @@
+package edit
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func testDeferredPlan() *plan.Plan {
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		Complete:         true,
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_instance.web",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action: plan.Action_UPDATE,
+					Values: []*plan.DynamicValue{{Msgpack: []byte("before")}, {Msgpack: []byte("after")}},
+				},
+				Private:      []byte("private"),
+				ActionReason: plan.ResourceInstanceActionReason_REPLACE_BY_REQUEST,
+			},
+			{
+				Addr:       "aws_instance.web",
+				DeposedKey: "00000001",
+				Change:     &plan.Change{Action: plan.Action_DELETE},
+			},
+			{
+				Addr:   "aws_instance.db",
+				Change: &plan.Change{Action: plan.Action_CREATE},
+			},
+		},
+		DeferredChanges: []*plan.DeferredResourceInstanceChange{
+			{
+				Deferred: &plan.Deferred{Reason: plan.DeferredReason_PROVIDER_CONFIG_UNKNOWN},
+				Change: &plan.ResourceInstanceChange{
+					Addr:   `module.k8s.kubernetes_manifest.cm`,
+					Change: &plan.Change{Action: plan.Action_CREATE},
+				},
+			},
+			{
+				Deferred: &plan.Deferred{Reason: plan.DeferredReason_INSTANCE_COUNT_UNKNOWN},
+				Change: &plan.ResourceInstanceChange{
+					Addr:   `aws_instance.worker[*]`,
+					Change: &plan.Change{Action: plan.Action_CREATE},
+				},
+			},
+			{
+				Deferred: &plan.Deferred{Reason: plan.DeferredReason_PROVIDER_CONFIG_UNKNOWN},
+				Change: &plan.ResourceInstanceChange{
+					Addr:   `module.k8s.kubernetes_manifest.secret`,
+					Change: &plan.Change{Action: plan.Action_READ},
+				},
+			},
+		},
+	}
+}
+
+func TestReportDeferredChanges(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	planPath := requirePlanFile(t, testDeferredPlan(), nil)
+	require.NoError(t, New(&Config{PlanPath: planPath}).ReportDeferredChanges(buf))
+	require.Equal(t, `INSTANCE_COUNT_UNKNOWN (1)
+  aws_instance.worker[*]  CREATE
+
+PROVIDER_CONFIG_UNKNOWN (2)
+  module.k8s.kubernetes_manifest.cm      CREATE
+  module.k8s.kubernetes_manifest.secret  READ
+`, buf.String())
+}
+
+func TestDeferAndUndeferChange(t *testing.T) {
+	t.Parallel()
+
+	orig := testDeferredPlan()
+	planPath := requirePlanFile(t, orig, nil)
+	dstPath := filepath.Join(t.TempDir(), "deferred.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).DeferChange("aws_instance.web", "absent_prereq"))
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	require.False(t, p.GetComplete())
+	require.Len(t, p.GetResourceChanges(), 1)
+	require.Equal(t, "aws_instance.db", p.GetResourceChanges()[0].GetAddr())
+	require.Len(t, p.GetDeferredChanges(), 5)
+	for i, d := range p.GetDeferredChanges()[3:] {
+		require.Equal(t, plan.DeferredReason_ABSENT_PREREQ, d.GetDeferred().GetReason())
+		require.True(t, proto.Equal(orig.GetResourceChanges()[i], d.GetChange()))
+	}
+
+	undeferredPath := filepath.Join(t.TempDir(), "undeferred.plan")
+	require.NoError(t, New(&Config{PlanPath: dstPath, DstPath: undeferredPath}).UndeferChange("aws_instance.web"))
+	p, err = New(&Config{PlanPath: undeferredPath}).readPlan()
+	require.NoError(t, err)
+	require.Len(t, p.GetDeferredChanges(), 3)
+	require.Len(t, p.GetResourceChanges(), 3)
+	require.True(t, proto.Equal(orig.GetResourceChanges()[0], p.GetResourceChanges()[1]))
+	require.True(t, proto.Equal(orig.GetResourceChanges()[1], p.GetResourceChanges()[2]))
+}
+
+func TestUndeferChangeRestoresComplete(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		targets  []string
+		complete bool
+	}{
+		"complete": {complete: true},
+		"targeted": {targets: []string{"aws_instance.web"}},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			planPath := requirePlanFile(t, &plan.Plan{
+				Version:          3,
+				TerraformVersion: "1.9.1",
+				Complete:         test.complete,
+				TargetAddrs:      test.targets,
+				ResourceChanges: []*plan.ResourceInstanceChange{
+					{Addr: "aws_instance.web", Change: &plan.Change{Action: plan.Action_CREATE}},
+				},
+			}, nil)
+			dstPath := filepath.Join(t.TempDir(), "deferred.plan")
+			require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).DeferChange("aws_instance.web", "ABSENT_PREREQ"))
+			p, err := New(&Config{PlanPath: dstPath}).readPlan()
+			require.NoError(t, err)
+			require.False(t, p.GetComplete())
+
+			require.NoError(t, New(&Config{PlanPath: dstPath, DstPath: dstPath}).UndeferChange("aws_instance.web"))
+			p, err = New(&Config{PlanPath: dstPath}).readPlan()
+			require.NoError(t, err)
+			require.Equal(t, test.complete, p.GetComplete())
+			require.Empty(t, p.GetDeferredChanges())
+		})
+	}
+}
+
+func TestDeferChangeErrors(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testDeferredPlan(), nil)
+	editor := New(&Config{PlanPath: planPath, DstPath: filepath.Join(t.TempDir(), "deferred.plan")})
+
+	require.ErrorContains(t, editor.DeferChange("aws_instance.web", "INVALID"), "invalid deferred reason")
+	require.ErrorContains(t, editor.DeferChange("aws_instance.web", "because"), "must be one of ABSENT_PREREQ")
+	require.ErrorContains(t, editor.DeferChange("aws_instance.nope", "ABSENT_PREREQ"), "no resource change found")
+	require.ErrorContains(t, editor.UndeferChange("aws_instance.web"), "no deferred change found")
+}
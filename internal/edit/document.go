@@ -0,0 +1,475 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// dynamicValue is a reference to a DynamicValue somewhere in a plan.
+type dynamicValue struct {
+	// keys is the location of the value in the dynamic values document, e.g.
+	// ["resource_changes", "aws_instance.web", "after"].
+	keys []string
+	// desc is a short description of the value which is used when editing it by itself.
+	desc  string
+	value *plan.DynamicValue
+	// resource is the change the value belongs to if it is a resource instance's value.
+	resource *plan.ResourceInstanceChange
+	// backend is the type of the backend if the value is the backend's configuration.
+	backend string
+}
+
+// key returns the location of the value in the dynamic values document in a human readable form
+// that can be used in error messages, e.g. resource_changes["aws_instance.web"].after.
+func (d dynamicValue) key() string {
+	return formatDocumentKey(d.keys)
+}
+
+// dynamicValues returns a reference to every msgpack encoded DynamicValue in the plan.
+func dynamicValues(p *plan.Plan) []dynamicValue {
+	values := []dynamicValue{}
+
+	names := []string{}
+	for k, v := range p.GetVariables() {
+		if v.GetMsgpack() != nil {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		values = append(values, dynamicValue{
+			keys:  []string{"variables", k},
+			desc:  "plan_variable_" + k,
+			value: p.GetVariables()[k],
+		})
+	}
+
+	changeValues := func(section string, descPrefix string, addr string, c *plan.Change, resource *plan.ResourceInstanceChange) {
+		for iv, v := range c.GetValues() {
+			if v.GetMsgpack() == nil {
+				continue
+			}
+
+			values = append(values, dynamicValue{
+				keys:     []string{section, addr, changeValueName(c.GetAction(), len(c.GetValues()), iv)},
+				desc:     fmt.Sprintf("%s_%s_%d", descPrefix, addr, iv),
+				value:    v,
+				resource: resource,
+			})
+		}
+
+		// Identities are described by the provider's identity schema rather than the resource
+		// schema, so they're not associated with the resource.
+		identities := []struct {
+			name  string
+			value *plan.DynamicValue
+		}{
+			{"before_identity", c.GetBeforeIdentity()},
+			{"after_identity", c.GetAfterIdentity()},
+			{"import_identity", c.GetImporting().GetIdentity()},
+		}
+		for _, id := range identities {
+			if id.value.GetMsgpack() == nil {
+				continue
+			}
+
+			values = append(values, dynamicValue{
+				keys:  []string{section, addr, id.name},
+				desc:  fmt.Sprintf("%s_%s_%s", descPrefix, addr, id.name),
+				value: id.value,
+			})
+		}
+	}
+
+	for _, c := range p.GetResourceChanges() {
+		changeValues("resource_changes", "resource_change", resourceInstanceChangeKey(c), c.GetChange(), c)
+	}
+
+	for _, d := range p.GetResourceDrift() {
+		changeValues("resource_drift", "resource_drift", resourceInstanceChangeKey(d), d.GetChange(), d)
+	}
+
+	for _, d := range p.GetDeferredChanges() {
+		changeValues("deferred_changes", "deferred_change", resourceInstanceChangeKey(d.GetChange()), d.GetChange().GetChange(), d.GetChange())
+	}
+
+	for _, o := range p.GetOutputChanges() {
+		changeValues("output_changes", "output_change", o.GetName(), o.GetChange(), nil)
+	}
+
+	actionValues := func(section string, descPrefix string, i int, a *plan.ActionInvocationInstance) {
+		if a.GetConfigValue().GetMsgpack() == nil {
+			return
+		}
+
+		values = append(values, dynamicValue{
+			keys:  []string{section, actionInvocationKey(a), "config"},
+			desc:  fmt.Sprintf("%s_%d", descPrefix, i),
+			value: a.GetConfigValue(),
+		})
+	}
+
+	for i, a := range p.GetActionInvocations() {
+		actionValues("action_invocations", "action_invocation", i, a)
+	}
+
+	for i, d := range p.GetDeferredActionInvocations() {
+		actionValues("deferred_action_invocations", "deferred_action_invocation", i, d.GetActionInvocation())
+	}
+
+	if c := p.GetBackend().GetConfig(); c != nil {
+		values = append(values, dynamicValue{
+			keys:    []string{"backend_config"},
+			desc:    "backend_config",
+			value:   c,
+			backend: p.GetBackend().GetType(),
+		})
+	}
+
+	return values
+}
+
+// changeValueName returns whether the value at index i of a Change's values is the before or the
+// after value. Changes that only carry one value have the after value for a create and the
+// before value for everything else.
+func changeValueName(action plan.Action, count int, i int) string {
+	switch {
+	case count == 1 && action == plan.Action_CREATE:
+		return "after"
+	case count == 1:
+		return "before"
+	case i == 0:
+		return "before"
+	case i == 1:
+		return "after"
+	default:
+		return strconv.Itoa(i)
+	}
+}
+
+// resourceInstanceChangeKey returns the address of the resource instance change, qualified with
+// the deposed key if the change applies to a deposed object.
+func resourceInstanceChangeKey(c *plan.ResourceInstanceChange) string {
+	if c.GetDeposedKey() == "" {
+		return c.GetAddr()
+	}
+
+	return c.GetAddr() + " deposed " + c.GetDeposedKey()
+}
+
+// actionInvocationKey returns the address of the action invocation, qualified with the resource
+// instance, event and position of the trigger if it was triggered by a resource's lifecycle, e.g.
+// action.aws_lambda_invoke.notify triggered by aws_instance.web after_create 0.0.
+func actionInvocationKey(a *plan.ActionInvocationInstance) string {
+	t := a.GetLifecycleActionTrigger()
+	if t == nil {
+		return a.GetAddr()
+	}
+
+	return fmt.Sprintf("%s triggered by %s %s %d.%d", a.GetAddr(), t.GetTriggeringResourceAddr(),
+		strings.ToLower(t.GetTriggerEvent().String()), t.GetActionTriggerBlockIndex(), t.GetActionsListIndex())
+}
+
+func formatDocumentKey(keys []string) string {
+	b := strings.Builder{}
+	for i, k := range keys {
+		switch {
+		case i == 0:
+			b.WriteString(k)
+		case i == len(keys)-1 && len(keys) > 2:
+			b.WriteString("." + k)
+		default:
+			b.WriteString("[" + strconv.Quote(k) + "]")
+		}
+	}
+
+	return b.String()
+}
+
+// formatCtyPath returns a human readable version of a cty path, e.g. .tags["env"] or .ips[0].
+func formatCtyPath(path cty.Path) string {
+	b := strings.Builder{}
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			b.WriteString("." + s.Name)
+		case cty.IndexStep:
+			switch {
+			case !s.Key.IsKnown() || s.Key.IsNull():
+				b.WriteString("[?]")
+			case s.Key.Type() == cty.String:
+				b.WriteString("[" + strconv.Quote(s.Key.AsString()) + "]")
+			case s.Key.Type() == cty.Number:
+				b.WriteString("[" + s.Key.AsBigFloat().Text('f', -1) + "]")
+			default:
+				b.WriteString("[?]")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// parseCtyPath parses a path in the format of formatCtyPath. The leading dot is optional, so
+// paths can also be written like tags["env"].
+func parseCtyPath(s string) (cty.Path, error) {
+	path := cty.Path{}
+	rest := strings.TrimPrefix(s, ".")
+	if rest == "" {
+		return nil, fmt.Errorf("invalid path %q: empty path", s)
+	}
+
+	for i := 0; rest != ""; i++ {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if rest[1:2] == `"` {
+				// The key is quoted and can contain brackets, so find the end of the string.
+				quoted, err := strconv.QuotedPrefix(rest[1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: %w", s, err)
+				}
+				end = len(quoted) + 1
+			}
+			if end < 0 || end >= len(rest) || rest[end] != ']' {
+				return nil, fmt.Errorf("invalid path %q: unterminated index", s)
+			}
+
+			key := rest[1:end]
+			if strings.HasPrefix(key, `"`) {
+				k, err := strconv.Unquote(key)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: %w", s, err)
+				}
+				path = path.Index(cty.StringVal(k))
+			} else {
+				n, err := cty.ParseNumberVal(key)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: invalid index %q", s, key)
+				}
+				path = path.Index(n)
+			}
+			rest = rest[end+1:]
+		case rest[0] == '.' || i == 0:
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty attribute name", s)
+			}
+			path = path.GetAttr(rest[:end])
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", s, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// editDynamicValuesDocument edits every DynamicValue in the plan that we're able to decode in a
+// single JSON document. The document is keyed by section, address and before/after so that
+// everything can be edited in one pass. Any values that we're unable to decode are edited one at
+// a time afterwards.
+func editDynamicValuesDocument(path string, config *Config, p *plan.Plan) error {
+	doc := map[string]any{}
+	types := map[string]cty.Type{}
+	decoded := []dynamicValue{}
+	undecodable := []dynamicValue{}
+
+	for _, d := range dynamicValues(p) {
+		schemaType := dynamicValueSchemaType(config.Schemas, d)
+		raw, ty, err := decodeDynamicValueJSON(d.value.GetMsgpack(), schemaType)
+		if err != nil && schemaType != cty.NilType {
+			return fmt.Errorf("unable to decode %s with its schema: %w", d.key(), err)
+		}
+		if err != nil {
+			undecodable = append(undecodable, d)
+			continue
+		}
+
+		setDocumentValue(doc, d.keys, raw)
+		types[d.key()] = ty
+		decoded = append(decoded, d)
+	}
+
+	if len(decoded) > 0 {
+		jsonBytes, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		docBytes, err := toEditingFormat(config.Format, jsonBytes)
+		if err != nil {
+			return err
+		}
+
+		tmpFilePath := filepath.Join(filepath.Dir(path), "dynamic-values"+formatExt(config.Format))
+		tmpFile, err := os.Create(tmpFilePath)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmpFilePath)
+
+		_, err = tmpFile.Write(docBytes)
+		if err != nil {
+			return err
+		}
+		tmpFile.Close()
+
+		err = editFile(config.TextEditorCmd, tmpFilePath)
+		if err != nil {
+			return err
+		}
+
+		tmpFile, err = os.Open(tmpFilePath)
+		if err != nil {
+			return err
+		}
+		defer tmpFile.Close()
+
+		docBytes, err = io.ReadAll(tmpFile)
+		if err != nil {
+			return err
+		}
+
+		jsonBytes, err = fromEditingFormat(config.Format, docBytes)
+		if err != nil {
+			return fmt.Errorf("invalid dynamic values document: %w", err)
+		}
+
+		edited := map[string]json.RawMessage{}
+		if err = flattenDocument(jsonBytes, doc, nil, edited); err != nil {
+			return fmt.Errorf("invalid dynamic values document: %w", err)
+		}
+
+		for _, d := range decoded {
+			key := d.key()
+			d.value.Msgpack, err = encodeDynamicValueJSON(edited[key], types[key])
+			if err != nil {
+				return fmt.Errorf("invalid dynamic values document: %s: %w", key, err)
+			}
+		}
+	}
+
+	for _, d := range undecodable {
+		if err := editDynamicValue(path, config, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeDynamicValueJSON decodes msgpack bytes into the JSON we use to edit them, along with the
+// type needed to turn the edited JSON back into msgpack. The type is inferred from the bytes
+// unless a type from the schema is given.
+func decodeDynamicValueJSON(bytes []byte, typ cty.Type) (json.RawMessage, cty.Type, error) {
+	var err error
+	if typ == cty.NilType {
+		typ, err = impliedType(bytes)
+		if err != nil {
+			return nil, cty.NilType, err
+		}
+	}
+
+	val, err := ctymsgpack.Unmarshal(bytes, typ)
+	if err != nil {
+		return nil, cty.NilType, err
+	}
+
+	raw, err := marshalValueJSON(val, typ)
+	if err != nil {
+		return nil, cty.NilType, err
+	}
+
+	return raw, typ, nil
+}
+
+// encodeDynamicValueJSON encodes JSON that was decoded with decodeDynamicValueJSON back into
+// msgpack bytes.
+func encodeDynamicValueJSON(raw json.RawMessage, typ cty.Type) ([]byte, error) {
+	val, err := unmarshalValueJSON(raw, typ)
+	if err != nil {
+		var pathErr cty.PathError
+		if errors.As(err, &pathErr) && len(pathErr.Path) > 0 {
+			return nil, fmt.Errorf("%s: %w", formatCtyPath(pathErr.Path), err)
+		}
+		return nil, err
+	}
+
+	return ctymsgpack.Marshal(val, typ)
+}
+
+func setDocumentValue(doc map[string]any, keys []string, raw json.RawMessage) {
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := doc[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			doc[k] = next
+		}
+		doc = next
+	}
+
+	doc[keys[len(keys)-1]] = raw
+}
+
+// flattenDocument walks an edited document in the same shape as the document we rendered and
+// collects every value by its document key. It returns an error that points at the offending key
+// if any keys have been added or removed.
+func flattenDocument(raw json.RawMessage, shape map[string]any, keys []string, out map[string]json.RawMessage) error {
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		if len(keys) == 0 {
+			return err
+		}
+		return fmt.Errorf("%s: expected an object: %w", formatDocumentKey(keys), err)
+	}
+
+	names := []string{}
+	for k := range obj {
+		names = append(names, k)
+	}
+	for k := range shape {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		key := append(append([]string{}, keys...), k)
+		v, inDoc := obj[k]
+		sub, inShape := shape[k]
+
+		switch {
+		case !inShape:
+			return fmt.Errorf("%s: unexpected key", formatDocumentKey(key))
+		case !inDoc:
+			return fmt.Errorf("%s: missing key", formatDocumentKey(key))
+		}
+
+		if m, ok := sub.(map[string]any); ok {
+			if err := flattenDocument(v, m, key, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		out[formatDocumentKey(key)] = v
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package edit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func requireDynamicValue(t *testing.T, val cty.Value, ty cty.Type) *plan.DynamicValue {
+	t.Helper()
+
+	b, err := ctymsgpack.Marshal(val, ty)
+	require.NoError(t, err)
+
+	return &plan.DynamicValue{Msgpack: b}
+}
+
+func testDynamicValuesPlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	ty := cty.Object(map[string]cty.Type{
+		"id":       cty.String,
+		"password": cty.String,
+	})
+
+	return &plan.Plan{
+		Variables: map[string]*plan.DynamicValue{
+			"token": requireDynamicValue(t, cty.StringVal("secret"), cty.DynamicPseudoType),
+		},
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr: "aws_instance.web",
+				Change: &plan.Change{
+					Action: plan.Action_CREATE,
+					Values: []*plan.DynamicValue{
+						requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+							"id":       cty.UnknownVal(cty.String),
+							"password": cty.StringVal("secret"),
+						}), ty),
+					},
+				},
+			},
+			{
+				Addr:       "aws_instance.web",
+				DeposedKey: "00000001",
+				Change: &plan.Change{
+					Action: plan.Action_DELETE,
+					Values: []*plan.DynamicValue{
+						requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+							"id":       cty.StringVal("i-1234"),
+							"password": cty.StringVal("secret"),
+						}), ty),
+					},
+				},
+			},
+		},
+		OutputChanges: []*plan.OutputChange{
+			{
+				Name: "password",
+				Change: &plan.Change{
+					Action: plan.Action_UPDATE,
+					Values: []*plan.DynamicValue{
+						requireDynamicValue(t, cty.StringVal("old-secret"), cty.String),
+						requireDynamicValue(t, cty.StringVal("secret"), cty.String),
+					},
+				},
+			},
+		},
+		Backend: &plan.Backend{
+			Type: "local",
+			// A value we're unable to decode as a cty value, it will be edited as a hex dump.
+			Config: &plan.DynamicValue{Msgpack: []byte{0xc4, 0x01, 0x00}},
+		},
+	}
+}
+
+func TestDynamicValues(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{}
+	descs := []string{}
+	for _, d := range dynamicValues(testDynamicValuesPlan(t)) {
+		keys = append(keys, d.key())
+		descs = append(descs, d.desc)
+	}
+
+	require.Equal(t, []string{
+		`variables["token"]`,
+		`resource_changes["aws_instance.web"].after`,
+		`resource_changes["aws_instance.web deposed 00000001"].before`,
+		`output_changes["password"].before`,
+		`output_changes["password"].after`,
+		`backend_config`,
+	}, keys)
+	require.Equal(t, []string{
+		"plan_variable_token",
+		"resource_change_aws_instance.web_0",
+		"resource_change_aws_instance.web deposed 00000001_0",
+		"output_change_password_0",
+		"output_change_password_1",
+		"backend_config",
+	}, descs)
+}
+
+func TestEditDynamicValuesDocument(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tfplan")
+	p := testDynamicValuesPlan(t)
+	config := &Config{TextEditorCmd: sedEditor(t, `s/secret/redacted/g`)}
+	require.NoError(t, editDynamicValuesDocument(path, config, p))
+
+	val, err := ctymsgpack.Unmarshal(p.GetVariables()["token"].GetMsgpack(), cty.DynamicPseudoType)
+	require.NoError(t, err)
+	require.Equal(t, "redacted", val.AsString())
+
+	ty := cty.Object(map[string]cty.Type{"id": cty.String, "password": cty.String})
+	val, err = ctymsgpack.Unmarshal(p.GetResourceChanges()[0].GetChange().GetValues()[0].GetMsgpack(), ty)
+	require.NoError(t, err)
+	require.False(t, val.GetAttr("id").IsKnown())
+	require.Equal(t, "redacted", val.GetAttr("password").AsString())
+
+	val, err = ctymsgpack.Unmarshal(p.GetResourceChanges()[1].GetChange().GetValues()[0].GetMsgpack(), ty)
+	require.NoError(t, err)
+	require.Equal(t, "redacted", val.GetAttr("password").AsString())
+
+	val, err = ctymsgpack.Unmarshal(p.GetOutputChanges()[0].GetChange().GetValues()[0].GetMsgpack(), cty.String)
+	require.NoError(t, err)
+	require.Equal(t, "old-redacted", val.AsString())
+
+	require.Equal(t, []byte{0xc4, 0x01, 0x00}, p.GetBackend().GetConfig().GetMsgpack())
+}
+
+func TestEditDynamicValuesDocumentErrors(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		editor string
+		err    string
+	}{
+		"renamed key": {
+			editor: sedEditor(t, `s/"after"/"afterr"/`),
+			err:    `output_changes["password"].after: missing key`,
+		},
+		"added key": {
+			editor: sedEditor(t, `s/"token":/"new":1,"token":/`),
+			err:    `variables["new"]: unexpected key`,
+		},
+		"invalid value": {
+			editor: sedEditor(t, `s/"password":.*"secret"/"password":{}/`),
+			err:    `resource_changes["aws_instance.web"].after: .password`,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "tfplan")
+			err := editDynamicValuesDocument(path, &Config{TextEditorCmd: test.editor}, testDynamicValuesPlan(t))
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestParseCtyPath(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []cty.Path{
+		cty.GetAttrPath("password"),
+		cty.GetAttrPath("tags").Index(cty.StringVal("a.b[0]")),
+		cty.GetAttrPath("disks").Index(cty.NumberIntVal(0)).GetAttr("key"),
+	} {
+		got, err := parseCtyPath(formatCtyPath(path))
+		require.NoError(t, err)
+		require.True(t, path.Equals(got), formatCtyPath(path))
+	}
+
+	got, err := parseCtyPath(`user[1].token`)
+	require.NoError(t, err)
+	require.True(t, cty.GetAttrPath("user").Index(cty.NumberIntVal(1)).GetAttr("token").Equals(got))
+
+	for _, s := range []string{"", "user[0", "user..name", `tags["a]`, "user[x]"} {
+		_, err := parseCtyPath(s)
+		require.Error(t, err, s)
+	}
+}
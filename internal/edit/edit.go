@@ -13,20 +13,29 @@ import (
 	"time"
 
 	"github.com/ugorji/go/codec"
-	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"github.com/zclconf/go-cty/cty"
 	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
 	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
 	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
 )
 
 type Config struct {
-	TextEditorCmd string
-	BinEditorCmd  string
-	PlanPath      string
-	DstPath       string
+	TextEditorCmd  string
+	BinEditorCmd   string
+	PlanPath       string
+	DstPath        string
+	SingleDocument bool
+	Format         string
+	// Schemas are the provider schemas used to decode dynamic values. When they're not set, or
+	// don't describe a value, the value's type is inferred from its msgpack encoding.
+	Schemas *schema.Schemas
+	// Selectors limit redaction to the resource instances they select. Every value is redacted
+	// when there are none.
+	Selectors []addrs.Selector
 }
 
 type Editor struct {
@@ -38,6 +47,21 @@ func New(cfg *Config) *Editor {
 }
 
 func (e *Editor) Edit() error {
+	if err := validateFormat(e.Format); err != nil {
+		return err
+	}
+
+	kind, err := e.fileKind()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case jsonPlanFile:
+		return e.editJSONPlan()
+	case stateFile:
+		return e.editState()
+	}
+
 	dir, err := e.unzipPlan()
 	if err != nil {
 		return err
@@ -47,6 +71,32 @@ func (e *Editor) Edit() error {
 		return err
 	}
 
+	// Check the edited files against each other before the plan is written.
+	bytes, err := os.ReadFile(filepath.Join(dir, "tfplan"))
+	if err != nil {
+		return fmt.Errorf("unable to read tfplan from Terraform plan: %w", err)
+	}
+	p := &plan.Plan{}
+	if err = proto.Unmarshal(bytes, p); err != nil {
+		return err
+	}
+
+	warnings, err := checkPlanStates(e.Schemas, dir, p)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Printf("warning: tfplan: %s\n", w)
+	}
+
+	if err = checkPlanLocks(dir, p); err != nil {
+		return err
+	}
+
+	if err = checkConfigSnapshot(dir, p); err != nil {
+		return err
+	}
+
 	if err = e.zipPlan(dir); err != nil {
 		return err
 	}
@@ -103,6 +153,82 @@ func (e *Editor) unzipPlan() (string, error) {
 	return tmpDir, nil
 }
 
+// updatePlan unpacks the plan at PlanPath, applies the update to the tfplan, and writes the
+// updated plan to DstPath.
+func (e *Editor) updatePlan(update func(p *plan.Plan) error) error {
+	return e.updatePlanDir(func(_ string, p *plan.Plan) error {
+		return update(p)
+	})
+}
+
+// updatePlanDir is like updatePlan, but the update can also change the other files of the plan,
+// which is unpacked in dir.
+func (e *Editor) updatePlanDir(update func(dir string, p *plan.Plan) error) error {
+	dir, err := e.unzipPlan()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tfplan")
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read tfplan from Terraform plan: %w", err)
+	}
+
+	p, err := unmarshalPlan(bytes)
+	if err != nil {
+		return err
+	}
+
+	if err = update(dir, p); err != nil {
+		return err
+	}
+
+	bytes, err = marshalPlan(p)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, bytes, 0o644); err != nil {
+		return err
+	}
+
+	return e.zipPlan(dir)
+}
+
+// readPlan reads the tfplan from the plan at PlanPath without unpacking the rest of the plan.
+func (e *Editor) readPlan() (*plan.Plan, error) {
+	bytes, err := e.readPlanFile("tfplan")
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalPlan(bytes)
+}
+
+// readPlanFile reads the named file from the plan at PlanPath without unpacking the rest of the
+// plan.
+func (e *Editor) readPlanFile(name string) ([]byte, error) {
+	if e == nil || e.PlanPath == "" {
+		return nil, errors.New("you must provide a path to a Terraform plan")
+	}
+
+	reader, err := zip.OpenReader(e.PlanPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	zf, err := reader.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from Terraform plan: %w", name, err)
+	}
+	defer zf.Close()
+
+	return io.ReadAll(zf)
+}
+
 func (d *Editor) zipPlan(dir string) error {
 	if dir == "" {
 		return errors.New("zip plan: no directory given")
@@ -174,16 +300,11 @@ func copy(in proto.Message, out proto.Message) error {
 		return errors.New("you must provide a source and destination message to copy")
 	}
 
-	bytes, err := prototext.Marshal(in)
-	if err != nil {
-		return err
-	}
-
-	if len(bytes) == 0 {
-		return nil
-	}
+	// Merging rather than round tripping through an encoding keeps any unknown fields.
+	proto.Reset(out)
+	proto.Merge(out, in)
 
-	return prototext.Unmarshal(bytes, out)
+	return nil
 }
 
 func editTFPlan(config *Config, path string) error {
@@ -202,8 +323,7 @@ func editTFPlan(config *Config, path string) error {
 		return nil
 	}
 
-	origPlan := &plan.Plan{}
-	err = proto.Unmarshal(bytes, origPlan)
+	origPlan, err := unmarshalPlan(bytes)
 	if err != nil {
 		return err
 	}
@@ -212,7 +332,7 @@ func editTFPlan(config *Config, path string) error {
 	// then for every value that is. We then combine our values together by applying the msgpack only
 	// plan on top. This allows for more specific editing of values that are msgpack encoded and
 	// otherwise very difficult to edit as text.
-	planSansMsgpack, err := editTFPlanNoMsgPack(path, config.TextEditorCmd, origPlan)
+	planSansMsgpack, err := editTFPlanNoMsgPack(path, config, origPlan)
 	if err != nil {
 		return err
 	}
@@ -227,7 +347,21 @@ func editTFPlan(config *Config, path string) error {
 		return err
 	}
 
-	npBytes, err := proto.Marshal(np)
+	// The plan without dynamic values went through protojson, which drops any fields that aren't
+	// in the vendored schema. Put them back where they were.
+	for _, path := range collectUnknownFields(origPlan).restore(np) {
+		fmt.Printf("warning: tfplan: %s was removed, dropping its unknown fields\n", path)
+	}
+
+	if err = editGeneratedConfigs(path, config, np); err != nil {
+		return err
+	}
+
+	if err = validatePlan(config.Schemas, np); err != nil {
+		return fmt.Errorf("edited plan does not conform to the provider schemas:\n%w", err)
+	}
+
+	npBytes, err := marshalPlan(np)
 	if err != nil {
 		return err
 	}
@@ -274,6 +408,28 @@ func combinePlans(sans *plan.Plan, only *plan.Plan) (*plan.Plan, error) {
 
 			np.ResourceChanges[ic].Change.Values = v
 		}
+
+		if gc := c.GetChange().GetGeneratedConfig(); gc != "" {
+			if np.GetResourceChanges()[ic] == nil {
+				np.ResourceChanges[ic] = &plan.ResourceInstanceChange{}
+			}
+			if np.GetResourceChanges()[ic].GetChange() == nil {
+				np.ResourceChanges[ic].Change = &plan.Change{}
+			}
+
+			np.ResourceChanges[ic].Change.GeneratedConfig = gc
+		}
+
+		if hasIdentities(c.GetChange()) {
+			if np.GetResourceChanges()[ic] == nil {
+				np.ResourceChanges[ic] = &plan.ResourceInstanceChange{}
+			}
+			if np.GetResourceChanges()[ic].GetChange() == nil {
+				np.ResourceChanges[ic].Change = &plan.Change{}
+			}
+
+			combineIdentities(np.ResourceChanges[ic].Change, c.GetChange())
+		}
 	}
 
 	for id, c := range only.GetResourceDrift() {
@@ -291,6 +447,17 @@ func combinePlans(sans *plan.Plan, only *plan.Plan) (*plan.Plan, error) {
 
 			np.ResourceDrift[id].Change.Values = v
 		}
+
+		if hasIdentities(c.GetChange()) {
+			if np.GetResourceDrift()[id] == nil {
+				np.ResourceDrift[id] = &plan.ResourceInstanceChange{}
+			}
+			if np.GetResourceDrift()[id].GetChange() == nil {
+				np.ResourceDrift[id].Change = &plan.Change{}
+			}
+
+			combineIdentities(np.ResourceDrift[id].Change, c.GetChange())
+		}
 	}
 
 	for ic, d := range only.GetDeferredChanges() {
@@ -313,6 +480,12 @@ func combinePlans(sans *plan.Plan, only *plan.Plan) (*plan.Plan, error) {
 		if values := d.GetChange().GetChange().GetValues(); values != nil {
 			np.DeferredChanges[ic].Change.Change.Values = values
 		}
+
+		if gc := d.GetChange().GetChange().GetGeneratedConfig(); gc != "" {
+			np.DeferredChanges[ic].Change.Change.GeneratedConfig = gc
+		}
+
+		combineIdentities(np.DeferredChanges[ic].Change.Change, d.GetChange().GetChange())
 	}
 
 	for io, o := range only.GetOutputChanges() {
@@ -333,6 +506,35 @@ func combinePlans(sans *plan.Plan, only *plan.Plan) (*plan.Plan, error) {
 		}
 	}
 
+	for ia, a := range only.GetActionInvocations() {
+		if c := a.GetConfigValue(); c != nil {
+			if ia >= len(np.GetActionInvocations()) {
+				return nil, fmt.Errorf("action_invocations[%d]: the edited plan no longer has the action invocation the config value belongs to", ia)
+			}
+			if np.GetActionInvocations()[ia] == nil {
+				np.ActionInvocations[ia] = &plan.ActionInvocationInstance{}
+			}
+
+			np.ActionInvocations[ia].ConfigValue = c
+		}
+	}
+
+	for ia, d := range only.GetDeferredActionInvocations() {
+		if c := d.GetActionInvocation().GetConfigValue(); c != nil {
+			if ia >= len(np.GetDeferredActionInvocations()) {
+				return nil, fmt.Errorf("deferred_action_invocations[%d]: the edited plan no longer has the action invocation the config value belongs to", ia)
+			}
+			if np.GetDeferredActionInvocations()[ia] == nil {
+				np.DeferredActionInvocations[ia] = &plan.DeferredActionInvocation{}
+			}
+			if np.GetDeferredActionInvocations()[ia].GetActionInvocation() == nil {
+				np.DeferredActionInvocations[ia].ActionInvocation = &plan.ActionInvocationInstance{}
+			}
+
+			np.DeferredActionInvocations[ia].ActionInvocation.ConfigValue = c
+		}
+	}
+
 	if c := only.GetBackend().GetConfig(); c != nil {
 		if np.GetBackend() == nil {
 			np.Backend = &plan.Backend{}
@@ -344,9 +546,46 @@ func combinePlans(sans *plan.Plan, only *plan.Plan) (*plan.Plan, error) {
 	return np, nil
 }
 
+// hasIdentities returns whether the change has any msgpack encoded resource identities.
+func hasIdentities(c *plan.Change) bool {
+	return c.GetBeforeIdentity() != nil || c.GetAfterIdentity() != nil || c.GetImporting().GetIdentity() != nil
+}
+
+// combineIdentities sets the resource identities of the change to those of the change that only
+// has msgpack values.
+func combineIdentities(dst *plan.Change, only *plan.Change) {
+	if v := only.GetBeforeIdentity(); v != nil {
+		dst.BeforeIdentity = v
+	}
+
+	if v := only.GetAfterIdentity(); v != nil {
+		dst.AfterIdentity = v
+	}
+
+	if v := only.GetImporting().GetIdentity(); v != nil {
+		if dst.GetImporting() == nil {
+			dst.Importing = &plan.Importing{}
+		}
+		dst.Importing.Identity = v
+	}
+}
+
+// stripIdentities removes the msgpack encoded resource identities from the change.
+func stripIdentities(c *plan.Change) {
+	if c == nil {
+		return
+	}
+
+	c.BeforeIdentity = nil
+	c.AfterIdentity = nil
+	if c.GetImporting() != nil {
+		c.Importing.Identity = nil
+	}
+}
+
 func editTFPlanNoMsgPack(
 	path string,
-	editorCmd string,
+	config *Config,
 	p *plan.Plan,
 ) (*plan.Plan, error) {
 	np := &plan.Plan{}
@@ -364,17 +603,38 @@ func editTFPlanNoMsgPack(
 		if c.GetChange().GetValues() != nil {
 			np.ResourceChanges[ic].Change.Values = nil
 		}
+
+		// Generated config is edited as HCL in its own pass.
+		if c.GetChange().GetGeneratedConfig() != "" {
+			np.ResourceChanges[ic].Change.GeneratedConfig = ""
+		}
+
+		stripIdentities(c.GetChange())
 	}
 
 	for id, d := range np.GetResourceDrift() {
 		if d.GetChange().GetValues() != nil {
 			np.ResourceDrift[id].Change.Values = nil
 		}
+
+		stripIdentities(d.GetChange())
 	}
 
 	for id, d := range np.GetDeferredChanges() {
 		if change := d.GetChange().GetChange(); change != nil {
 			np.DeferredChanges[id].Change.Change.Values = nil
+			np.DeferredChanges[id].Change.Change.GeneratedConfig = ""
+			stripIdentities(change)
+		}
+	}
+
+	for _, a := range np.GetActionInvocations() {
+		a.ConfigValue = nil
+	}
+
+	for _, d := range np.GetDeferredActionInvocations() {
+		if a := d.GetActionInvocation(); a != nil {
+			a.ConfigValue = nil
 		}
 	}
 
@@ -397,20 +657,25 @@ func editTFPlanNoMsgPack(
 		return nil, err
 	}
 
-	tmpFilePath := filepath.Join(filepath.Dir(path), "tfplan-sans-dynamic-values.json")
+	docBytes, err := toEditingFormat(config.Format, jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFilePath := filepath.Join(filepath.Dir(path), "tfplan-sans-dynamic-values"+formatExt(config.Format))
 	tmpFile, err := os.Create(tmpFilePath)
 	if err != nil {
 		return nil, err
 	}
 	defer os.Remove(tmpFilePath)
 
-	_, err = tmpFile.Write(jsonBytes)
+	_, err = tmpFile.Write(docBytes)
 	if err != nil {
 		return nil, err
 	}
 	tmpFile.Close()
 
-	err = editFile(editorCmd, tmpFilePath)
+	err = editFile(config.TextEditorCmd, tmpFilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +686,12 @@ func editTFPlanNoMsgPack(
 	}
 	defer tmpFile.Close()
 
-	bytes, err := io.ReadAll(tmpFile)
+	docBytes, err = io.ReadAll(tmpFile)
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := fromEditingFormat(config.Format, docBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -438,67 +708,17 @@ func editTFPlanOnlyMsgPack(path string, config *Config, p *plan.Plan) (*plan.Pla
 		return nil, err
 	}
 
-	// Update most of the msgpack values by converting them into JSON to allow easier editing.
-	for k, v := range np.GetVariables() {
-		if v.GetMsgpack() == nil {
-			continue
-		}
-
-		if err := editDynamicValue(path, config, v, "plan_variable_"+k); err != nil {
+	if config.SingleDocument {
+		if err := editDynamicValuesDocument(path, config, np); err != nil {
 			return nil, err
 		}
-	}
-
-	for _, c := range np.GetResourceChanges() {
-		for iv, v := range c.GetChange().GetValues() {
-			if v.GetMsgpack() == nil {
-				continue
-			}
 
-			if err := editDynamicValue(path, config, v, fmt.Sprintf("resource_change_%s_%d", c.GetAddr(), iv)); err != nil {
-				return nil, err
-			}
-		}
+		return np, nil
 	}
 
-	for _, d := range np.GetResourceDrift() {
-		for iv, v := range d.GetChange().GetValues() {
-			if v.GetMsgpack() == nil {
-				continue
-			}
-
-			if err := editDynamicValue(path, config, v, fmt.Sprintf("resource_drift_%s_%d", d.GetAddr(), iv)); err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	for _, d := range np.GetDeferredChanges() {
-		for iv, v := range d.GetChange().GetChange().GetValues() {
-			if v.GetMsgpack() == nil {
-				continue
-			}
-
-			if err := editDynamicValue(path, config, v, fmt.Sprintf("deferred_change_%s_%d", d.GetChange().GetAddr(), iv)); err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	for _, d := range np.GetOutputChanges() {
-		for iv, v := range d.GetChange().GetValues() {
-			if v.GetMsgpack() == nil {
-				continue
-			}
-
-			if err := editDynamicValue(path, config, v, fmt.Sprintf("output_change_%s_%d", d.Name, iv)); err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	if c := np.GetBackend().GetConfig(); c != nil {
-		if err := editDynamicValue(path, config, c, "backend_config"); err != nil {
+	// Update most of the msgpack values by converting them into JSON to allow easier editing.
+	for _, d := range dynamicValues(np) {
+		if err := editDynamicValue(path, config, d); err != nil {
 			return nil, err
 		}
 	}
@@ -506,7 +726,8 @@ func editTFPlanOnlyMsgPack(path string, config *Config, p *plan.Plan) (*plan.Pla
 	return np, nil
 }
 
-func editDynamicValue(path string, config *Config, d *plan.DynamicValue, desc string) error {
+func editDynamicValue(path string, config *Config, dv dynamicValue) error {
+	d, desc := dv.value, dv.desc
 	if d == nil {
 		return nil
 	}
@@ -516,18 +737,75 @@ func editDynamicValue(path string, config *Config, d *plan.DynamicValue, desc st
 		return nil
 	}
 
-	// If we're editing values that have been encoded with as a cty.DynamicPseudoType this will fail
-	// and we'll fall back on our raw strategy.
+	// Values that have been encoded with as a cty.DynamicPseudoType are edited as cty's typed JSON.
+	// If we're unable to decode the value at all we'll fall back on editing the raw bytes, either
+	// with the binary editor if one was given or as a hex dump with the text editor. Values that
+	// are described by a schema must always match it.
+	typ := dynamicValueSchemaType(config.Schemas, dv)
 	var err error
-	d.Msgpack, err = editDynamicValueKnownCTYType(path, config.TextEditorCmd, bytes, desc)
+	d.Msgpack, err = editDynamicValueKnownCTYType(path, config.TextEditorCmd, config.Format, bytes, typ, desc)
 	if err == nil {
 		return nil
 	}
+	if typ != cty.NilType {
+		return err
+	}
+
+	if config.BinEditorCmd != "" {
+		d.Msgpack, err = editDynamicValueUnknownType(path, config.BinEditorCmd, bytes, desc)
+		return err
+	}
 
-	d.Msgpack, err = editDynamicValueUnknownType(path, config.BinEditorCmd, bytes, desc)
+	d.Msgpack, err = editDynamicValueHexDump(path, config.TextEditorCmd, bytes, desc)
 	return err
 }
 
+func editDynamicValueHexDump(path string, editorCmd string, bytes []byte, desc string) ([]byte, error) {
+	if len(bytes) == 0 {
+		return bytes, nil
+	}
+
+	tmpFilePath := filepath.Join(filepath.Dir(path), "dynamic-value-hex-dump-"+strings.ReplaceAll(desc, " ", "-"))
+	tmpFile, err := os.Create(tmpFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpFilePath)
+
+	_, err = tmpFile.Write(hexDump(bytes, desc))
+	if err != nil {
+		return nil, err
+	}
+	tmpFile.Close()
+
+	err = editFile(editorCmd, tmpFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFile, err = os.Open(tmpFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer tmpFile.Close()
+
+	doc, err := io.ReadAll(tmpFile)
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err = parseHexDump(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse edited hex dump of dynamic value: %s: %w", desc, err)
+	}
+
+	if err = validateMsgpack(bytes); err != nil {
+		return nil, fmt.Errorf("edited dynamic value is not valid msgpack: %s: %w", desc, err)
+	}
+
+	return bytes, nil
+}
+
 func editDynamicValueUnknownType(path string, editorCmd string, bytes []byte, desc string) ([]byte, error) {
 	if len(bytes) == 0 {
 		return bytes, nil
@@ -577,14 +855,19 @@ func editDynamicValueUnknownType(path string, editorCmd string, bytes []byte, de
 	return io.ReadAll(tmpFile)
 }
 
-func editDynamicValueKnownCTYType(path string, editorCmd string, bytes []byte, desc string) ([]byte, error) {
+// editDynamicValueKnownCTYType edits the value as the given type, or the type inferred from the
+// value if the type is cty.NilType.
+func editDynamicValueKnownCTYType(path string, editorCmd string, format string, bytes []byte, typ cty.Type, desc string) ([]byte, error) {
 	if len(bytes) == 0 {
 		return bytes, nil
 	}
 
-	typ, err := ctymsgpack.ImpliedType(bytes)
-	if err != nil {
-		return nil, fmt.Errorf("cannot edit dynamic value: %s, unable to infer data type: %w", desc, err)
+	var err error
+	if typ == cty.NilType {
+		typ, err = impliedType(bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot edit dynamic value: %s, unable to infer data type: %w", desc, err)
+		}
 	}
 
 	val, err := ctymsgpack.Unmarshal(bytes, typ)
@@ -592,19 +875,24 @@ func editDynamicValueKnownCTYType(path string, editorCmd string, bytes []byte, d
 		return nil, fmt.Errorf("cannot edit dynamic value: %s, unable to decode data type: %w", typ, err)
 	}
 
-	jsonBytes, err := ctyjson.Marshal(val, typ)
+	jsonBytes, err := marshalValueJSON(val, typ)
 	if err != nil {
 		return nil, fmt.Errorf("cannot edit dynamic value: %s, unable to encode data type to JSON for editing: %w", typ, err)
 	}
 
-	tmpFilePath := filepath.Join(filepath.Dir(path), "dynamic-value-known-type-"+strings.ReplaceAll(desc, " ", "-"))
+	docBytes, err := toEditingFormat(format, jsonBytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot edit dynamic value: %s, unable to encode data type to %s for editing: %w", typ, format, err)
+	}
+
+	tmpFilePath := filepath.Join(filepath.Dir(path), "dynamic-value-known-type-"+strings.ReplaceAll(desc, " ", "-")+formatExt(format))
 	tmpFile, err := os.Create(tmpFilePath)
 	if err != nil {
 		return nil, err
 	}
 	defer os.Remove(tmpFilePath)
 
-	_, err = tmpFile.Write(jsonBytes)
+	_, err = tmpFile.Write(docBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -621,12 +909,17 @@ func editDynamicValueKnownCTYType(path string, editorCmd string, bytes []byte, d
 	}
 	defer tmpFile.Close()
 
-	jsonBytes, err = io.ReadAll(tmpFile)
+	docBytes, err = io.ReadAll(tmpFile)
 	if err != nil {
 		return nil, err
 	}
 
-	val, err = ctyjson.Unmarshal(jsonBytes, typ)
+	jsonBytes, err = fromEditingFormat(format, docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode edited dynamic value: %w", err)
+	}
+
+	val, err = unmarshalValueJSON(jsonBytes, typ)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode edited dynamic value: %w", err)
 	}
@@ -664,9 +957,17 @@ func (e *Editor) editFilesIn(dir string) error {
 			return nil
 		}
 
-		if strings.HasSuffix(path, "/tfplan") {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case strings.HasSuffix(path, "/tfplan"):
 			return editTFPlan(e.Config, path)
-		} else {
+		case rel == "tfstate" || rel == "tfstate-prev":
+			return editStateFile(e.Config, path)
+		default:
 			return editFile(e.Config.TextEditorCmd, path)
 		}
 	})
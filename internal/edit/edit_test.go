@@ -1,10 +1,16 @@
 package edit
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
-	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/proto"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
 )
 
 func requireEqualResourceInstanceChanges(t *testing.T, e, a []*plan.ResourceInstanceChange) {
@@ -80,6 +86,23 @@ func requireEqualPlan(t *testing.T, e, a *plan.Plan) {
 	requireEqualResourceInstanceChanges(t, e.ResourceDrift, a.ResourceDrift)
 }
 
+// sedEditor returns an editor command that edits the file in place with the sed expressions. It
+// doesn't use sed -i, which GNU sed and BSD sed parse differently.
+func sedEditor(t *testing.T, exprs ...string) string {
+	t.Helper()
+
+	script := "#!/bin/sh\nsed"
+	for _, e := range exprs {
+		script += " -e '" + e + "'"
+	}
+	script += ` "$1" > "$1.tmp" && mv "$1.tmp" "$1"` + "\n"
+
+	path := filepath.Join(t.TempDir(), "sed-editor")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	return path
+}
+
 func TestCombinePlan(t *testing.T) {
 	t.Parallel()
 
@@ -472,3 +495,153 @@ func TestCombinePlan(t *testing.T) {
 	require.NoError(t, err)
 	requireEqualPlan(t, expected, comb)
 }
+
+// testNewerFieldsPlan returns a plan that has every field that was added after Terraform 1.9.
+func testNewerFieldsPlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	identityType := cty.Object(map[string]cty.Type{"id": cty.String})
+	identity := func(id string) *plan.DynamicValue {
+		return requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal(id)}), identityType)
+	}
+	config := func(fn string) *plan.DynamicValue {
+		return requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{"function_name": cty.StringVal(fn)}), cty.Object(map[string]cty.Type{"function_name": cty.String}))
+	}
+	change := func() *plan.Change {
+		return &plan.Change{
+			Action:         plan.Action_UPDATE,
+			BeforeIdentity: identity("i-1234"),
+			AfterIdentity:  identity("i-1234"),
+			Importing:      &plan.Importing{Unknown: true, Identity: identity("i-1234")},
+		}
+	}
+	invocation := func(fn string) *plan.ActionInvocationInstance {
+		return &plan.ActionInvocationInstance{
+			Addr:        "action.aws_lambda_invoke.notify",
+			Provider:    `provider["registry.terraform.io/hashicorp/aws"]`,
+			ConfigValue: config(fn),
+		}
+	}
+
+	lifecycle := invocation("notify")
+	lifecycle.ActionTrigger = &plan.ActionInvocationInstance_LifecycleActionTrigger{
+		LifecycleActionTrigger: &plan.LifecycleActionTrigger{
+			TriggeringResourceAddr: "aws_instance.web",
+			TriggerEvent:           plan.ActionTriggerEvent_AFTER_CREATE,
+			ActionsListIndex:       1,
+		},
+	}
+	invoke := invocation("notify")
+	invoke.ActionTrigger = &plan.ActionInvocationInstance_InvokeActionTrigger{InvokeActionTrigger: &plan.InvokeActionTrigger{}}
+
+	return &plan.Plan{
+		Version:            3,
+		TerraformVersion:   "1.14.0",
+		ApplyTimeVariables: []string{"token"},
+		ResourceChanges:    []*plan.ResourceInstanceChange{{Addr: "aws_instance.web", Change: change()}},
+		ResourceDrift:      []*plan.ResourceInstanceChange{{Addr: "aws_instance.web", Change: change()}},
+		DeferredChanges: []*plan.DeferredResourceInstanceChange{
+			{
+				Deferred: &plan.Deferred{Reason: plan.DeferredReason_PROVIDER_CONFIG_UNKNOWN},
+				Change:   &plan.ResourceInstanceChange{Addr: "aws_instance.db", Change: change()},
+			},
+		},
+		ActionInvocations: []*plan.ActionInvocationInstance{lifecycle, invoke},
+		DeferredActionInvocations: []*plan.DeferredActionInvocation{
+			{
+				Deferred:         &plan.Deferred{Reason: plan.DeferredReason_DEFERRED_PREREQ},
+				ActionInvocation: invocation("later"),
+			},
+		},
+	}
+}
+
+func TestEditTFPlanNewerFields(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{
+		`resource_changes["aws_instance.web"].before_identity`,
+		`resource_changes["aws_instance.web"].after_identity`,
+		`resource_changes["aws_instance.web"].import_identity`,
+		`resource_drift["aws_instance.web"].before_identity`,
+		`resource_drift["aws_instance.web"].after_identity`,
+		`resource_drift["aws_instance.web"].import_identity`,
+		`deferred_changes["aws_instance.db"].before_identity`,
+		`deferred_changes["aws_instance.db"].after_identity`,
+		`deferred_changes["aws_instance.db"].import_identity`,
+		`action_invocations["action.aws_lambda_invoke.notify triggered by aws_instance.web after_create 0.1"].config`,
+		`action_invocations["action.aws_lambda_invoke.notify"].config`,
+		`deferred_action_invocations["action.aws_lambda_invoke.notify"].config`,
+	}, func() []string {
+		keys := []string{}
+		for _, d := range dynamicValues(testNewerFieldsPlan(t)) {
+			keys = append(keys, d.key())
+		}
+		return keys
+	}())
+
+	for _, singleDocument := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "tfplan")
+		orig, err := proto.Marshal(testNewerFieldsPlan(t))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, orig, 0o644))
+
+		// Every field survives an edit that doesn't change anything.
+		config := &Config{TextEditorCmd: "true", Format: FormatJSON, SingleDocument: singleDocument}
+		require.NoError(t, editTFPlan(config, path))
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		p := &plan.Plan{}
+		require.NoError(t, proto.Unmarshal(b, p))
+		require.True(t, proto.Equal(testNewerFieldsPlan(t), p))
+
+		// The identities and action configuration are edited as values, the rest as the plan.
+		config.TextEditorCmd = sedEditor(t, `s/i-1234/i-5678/`, `s/AFTER_CREATE/BEFORE_CREATE/`, `s/later/paused/`)
+		require.NoError(t, editTFPlan(config, path))
+		b, err = os.ReadFile(path)
+		require.NoError(t, err)
+		p = &plan.Plan{}
+		require.NoError(t, proto.Unmarshal(b, p))
+
+		requireString := func(dv *plan.DynamicValue, attr string, expected string) {
+			t.Helper()
+			ty, err := ctymsgpack.ImpliedType(dv.GetMsgpack())
+			require.NoError(t, err)
+			val, err := ctymsgpack.Unmarshal(dv.GetMsgpack(), ty)
+			require.NoError(t, err)
+			require.Equal(t, expected, val.GetAttr(attr).AsString())
+		}
+		for _, c := range []*plan.Change{
+			p.GetResourceChanges()[0].GetChange(),
+			p.GetResourceDrift()[0].GetChange(),
+			p.GetDeferredChanges()[0].GetChange().GetChange(),
+		} {
+			requireString(c.GetBeforeIdentity(), "id", "i-5678")
+			requireString(c.GetAfterIdentity(), "id", "i-5678")
+			requireString(c.GetImporting().GetIdentity(), "id", "i-5678")
+			require.True(t, c.GetImporting().GetUnknown())
+		}
+		require.Equal(t, plan.ActionTriggerEvent_BEFORE_CREATE, p.GetActionInvocations()[0].GetLifecycleActionTrigger().GetTriggerEvent())
+		require.NotNil(t, p.GetActionInvocations()[1].GetInvokeActionTrigger())
+		requireString(p.GetActionInvocations()[0].GetConfigValue(), "function_name", "notify")
+		requireString(p.GetDeferredActionInvocations()[0].GetActionInvocation().GetConfigValue(), "function_name", "paused")
+		require.Equal(t, plan.DeferredReason_DEFERRED_PREREQ, p.GetDeferredActionInvocations()[0].GetDeferred().GetReason())
+		require.Equal(t, []string{"token"}, p.GetApplyTimeVariables())
+	}
+}
+
+func TestCombinePlanRemovedActionInvocations(t *testing.T) {
+	t.Parallel()
+
+	only := &plan.Plan{
+		ActionInvocations:         []*plan.ActionInvocationInstance{{ConfigValue: &plan.DynamicValue{Msgpack: []byte{0x80}}}},
+		DeferredActionInvocations: []*plan.DeferredActionInvocation{{}},
+	}
+	_, err := combinePlans(&plan.Plan{}, only)
+	require.EqualError(t, err, "action_invocations[0]: the edited plan no longer has the action invocation the config value belongs to")
+
+	only.ActionInvocations = nil
+	only.DeferredActionInvocations[0].ActionInvocation = &plan.ActionInvocationInstance{ConfigValue: &plan.DynamicValue{Msgpack: []byte{0x80}}}
+	_, err = combinePlans(&plan.Plan{}, only)
+	require.EqualError(t, err, "deferred_action_invocations[0]: the edited plan no longer has the action invocation the config value belongs to")
+}
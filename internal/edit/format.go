@@ -0,0 +1,250 @@
+package edit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The formats that editing documents can be rendered in. Documents are always produced and
+// consumed as JSON, other formats are converted to and from JSON around the editor.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// validateFormat returns an error if the format is not a supported editing format.
+func validateFormat(format string) error {
+	switch format {
+	case "", FormatJSON, FormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of %q or %q", format, FormatJSON, FormatYAML)
+	}
+}
+
+// formatExt returns the file extension for editing documents in the format.
+func formatExt(format string) string {
+	if format == FormatYAML {
+		return ".yaml"
+	}
+
+	return ".json"
+}
+
+// toEditingFormat converts a JSON document into the editing format.
+func toEditingFormat(format string, jsonBytes []byte) ([]byte, error) {
+	if format != FormatYAML {
+		return jsonBytes, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+
+	node, err := jsonToYAMLNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert JSON to YAML: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}}); err != nil {
+		return nil, fmt.Errorf("failed to convert JSON to YAML: %w", err)
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fromEditingFormat converts a document in the editing format back into JSON.
+func fromEditingFormat(format string, b []byte) ([]byte, error) {
+	if format != FormatYAML {
+		return b, nil
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return []byte("null"), nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := yamlNodeToJSON(doc.Content[0], buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func jsonToYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				k, ok := key.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", key)
+				}
+
+				val, err := jsonToYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, yamlStringNode(k), val)
+			}
+
+			_, err = dec.Token()
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				val, err := jsonToYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, val)
+			}
+
+			_, err = dec.Token()
+			return node, err
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %s", v)
+		}
+	case string:
+		return yamlStringNode(v), nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected token %v", tok)
+	}
+}
+
+// yamlStringNode returns a string node, using a literal block scalar for multi-line strings.
+func yamlStringNode(s string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+	if strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+
+	return node
+}
+
+func yamlNodeToJSON(node *yaml.Node, buf *bytes.Buffer) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlNodeToJSON(node.Alias, buf)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: object keys must be strings", key.Line)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			k, err := encodeJSON(key.Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(k)
+			buf.WriteByte(':')
+
+			if err = yamlNodeToJSON(val, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, val := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := yamlNodeToJSON(val, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+		return nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				return fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			fmt.Fprint(buf, b)
+		case "!!int", "!!float":
+			n, err := yamlNumberToJSON(node.Value)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			buf.WriteString(n)
+		default:
+			s, err := encodeJSON(node.Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(s)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("line %d: unsupported YAML node", node.Line)
+	}
+}
+
+// yamlNumberToJSON converts a YAML number into a JSON number without losing precision. Numbers
+// that we wrote are already valid JSON numbers and are returned as-is.
+func yamlNumberToJSON(s string) (string, error) {
+	if json.Valid([]byte(s)) {
+		return s, nil
+	}
+
+	clean := strings.ReplaceAll(s, "_", "")
+	if i, ok := new(big.Int).SetString(clean, 0); ok {
+		return i.String(), nil
+	}
+
+	f, _, err := big.ParseFloat(clean, 10, 512, big.ToNearestEven)
+	if err != nil || f.IsInf() {
+		return "", fmt.Errorf("unable to represent %q as a JSON number", s)
+	}
+
+	return f.Text('g', -1), nil
+}
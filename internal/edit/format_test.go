@@ -0,0 +1,148 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func TestYAMLFormatRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{
+		"user_data": cty.String,
+		"policy":    cty.String,
+		"enabled":   cty.String,
+		"port":      cty.String,
+		"count":     cty.Number,
+		"ratio":     cty.Number,
+		"big":       cty.Number,
+		"tags":      cty.Map(cty.String),
+		"ips":       cty.List(cty.String),
+		"id":        cty.DynamicPseudoType,
+		"manifest":  cty.DynamicPseudoType,
+		"empty":     cty.Set(cty.String),
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"user_data": cty.StringVal("#!/bin/bash\necho 'hello world'\nexit 0\n"),
+		"policy":    cty.StringVal("{\n  \"Version\": \"2012-10-17\"\n}"),
+		"enabled":   cty.StringVal("true"),
+		"port":      cty.StringVal("8200"),
+		"count":     cty.NumberIntVal(3),
+		"ratio":     cty.NumberFloatVal(0.25),
+		"big":       cty.MustParseNumberVal("123456789012345678901234567890"),
+		"tags":      cty.MapVal(map[string]cty.Value{"Name": cty.StringVal("web"), "null": cty.StringVal("~")}),
+		"ips":       cty.ListVal([]cty.Value{cty.StringVal("10.0.0.1"), cty.UnknownVal(cty.String)}),
+		"id":        cty.DynamicVal,
+		"manifest": cty.ObjectVal(map[string]cty.Value{
+			"kind": cty.StringVal("ConfigMap"),
+		}),
+		"empty": cty.SetValEmpty(cty.String),
+	})
+	in, err := ctymsgpack.Marshal(val, ty)
+	require.NoError(t, err)
+
+	jsonBytes, err := marshalValueJSON(val, ty)
+	require.NoError(t, err)
+	yamlBytes, err := toEditingFormat(FormatYAML, jsonBytes)
+	require.NoError(t, err)
+	require.Contains(t, string(yamlBytes), "user_data: |\n  #!/bin/bash\n  echo 'hello world'\n  exit 0\n")
+	require.Contains(t, string(yamlBytes), "policy: |-\n")
+	require.Contains(t, string(yamlBytes), `enabled: "true"`)
+	require.Contains(t, string(yamlBytes), `$unknown: true`)
+
+	jsonBytes, err = fromEditingFormat(FormatYAML, yamlBytes)
+	require.NoError(t, err)
+	got, err := unmarshalValueJSON(jsonBytes, ty)
+	require.NoError(t, err)
+	out, err := ctymsgpack.Marshal(got, ty)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+}
+
+func TestFromEditingFormatYAML(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		in       string
+		expected string
+		err      string
+	}{
+		"hand written": {
+			in:       "name: web\ncount: 0x10\nratio: 1_000.5\nok: false\nnothing: ~\nlist: [a, b]\n",
+			expected: `{"name":"web","count":16,"ratio":1000.5,"ok":false,"nothing":null,"list":["a","b"]}`,
+		},
+		"aliases": {
+			in:       "a: &x {k: v}\nb: *x\n",
+			expected: `{"a":{"k":"v"},"b":{"k":"v"}}`,
+		},
+		"invalid YAML": {
+			in:  "a: [",
+			err: "invalid YAML",
+		},
+		"infinity": {
+			in:  "a: .inf",
+			err: "line 1",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := fromEditingFormat(FormatYAML, []byte(test.in))
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, test.expected, string(out))
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateFormat(""))
+	require.NoError(t, validateFormat(FormatJSON))
+	require.NoError(t, validateFormat(FormatYAML))
+	require.Error(t, validateFormat("toml"))
+}
+
+func TestYAMLFormatProtoJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	p := &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		Timestamp:        "2024-07-08T17:19:28Z",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_instance.web",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action:          plan.Action_CREATE,
+					GeneratedConfig: "resource \"aws_instance\" \"web\" {\n  ami = \"ami-1234\"\n}\n",
+				},
+				Private: []byte("private"),
+			},
+		},
+	}
+
+	jsonBytes, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(p)
+	require.NoError(t, err)
+	yamlBytes, err := toEditingFormat(FormatYAML, jsonBytes)
+	require.NoError(t, err)
+	require.Contains(t, string(yamlBytes), "generatedConfig: |\n")
+
+	jsonBytes, err = fromEditingFormat(FormatYAML, yamlBytes)
+	require.NoError(t, err)
+	got := &plan.Plan{}
+	require.NoError(t, protojson.Unmarshal(jsonBytes, got))
+	require.True(t, proto.Equal(p, got))
+}
@@ -0,0 +1,807 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// localBackendType is the type of the local backend's configuration, which is used when a plan is
+// rebuilt from JSON as the JSON plan doesn't record the backend.
+var localBackendType = cty.Object(map[string]cty.Type{
+	"path":          cty.String,
+	"workspace_dir": cty.String,
+})
+
+// jsonActions maps the actions of a change in a JSON plan to the action in the tfplan.
+var jsonActions = map[string]plan.Action{
+	"no-op":         plan.Action_NOOP,
+	"create":        plan.Action_CREATE,
+	"read":          plan.Action_READ,
+	"update":        plan.Action_UPDATE,
+	"delete":        plan.Action_DELETE,
+	"delete,create": plan.Action_DELETE_THEN_CREATE,
+	"create,delete": plan.Action_CREATE_THEN_DELETE,
+	"forget":        plan.Action_FORGET,
+	"create,forget": plan.Action_CREATE_THEN_FORGET,
+}
+
+// FromJSON rebuilds a binary plan from the JSON plan at PlanPath, i.e. the output of
+// `terraform show -json`, and writes it to DstPath. Resource values are encoded with the types
+// from the provider schemas, and the configuration in configDir becomes the plan's config
+// snapshot. The prior state is written as both tfstate and tfstate-prev, with the values from
+// before any drift in tfstate-prev.
+func (e *Editor) FromJSON(configDir string) error {
+	if e.Schemas == nil {
+		return errors.New("you must provide provider schemas to rebuild a plan from JSON")
+	}
+
+	doc, err := readJSONPlan(e.PlanPath)
+	if err != nil {
+		return err
+	}
+
+	p, err := planFromJSON(e.Schemas, doc)
+	if err != nil {
+		return err
+	}
+	tfplan, err := marshalPlan(p)
+	if err != nil {
+		return err
+	}
+
+	prior, prev, err := statesFromJSON(e.Schemas, doc)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-plan-from-json")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]*state.State{"tfstate": prior, "tfstate-prev": prev}
+	for _, name := range mapkeys.Sorted(files) {
+		b, err := files[name].Marshal()
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+			return err
+		}
+	}
+	if err = os.WriteFile(filepath.Join(dir, "tfplan"), tfplan, 0o644); err != nil {
+		return err
+	}
+
+	if err = writeConfigSnapshot(configDir, dir); err != nil {
+		return fmt.Errorf("unable to snapshot configuration: %w", err)
+	}
+
+	err = copyFile(filepath.Join(configDir, ".terraform.lock.hcl"), filepath.Join(dir, ".terraform.lock.hcl"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return e.zipPlan(dir)
+}
+
+// planFromJSON converts the JSON plan to a tfplan. Fields that the JSON plan doesn't have, like
+// the target and force-replace addresses and the providers' private data, are left empty.
+func planFromJSON(schemas *schema.Schemas, doc map[string]any) (*plan.Plan, error) {
+	p := &plan.Plan{
+		Version:          planFormatVersion,
+		TerraformVersion: asString(doc["terraform_version"]),
+		Timestamp:        asString(doc["timestamp"]),
+		Variables:        map[string]*plan.DynamicValue{},
+	}
+	if v, ok := doc["applyable"].(bool); ok {
+		p.Applyable = v
+	}
+	if v, ok := doc["complete"].(bool); ok {
+		p.Complete = v
+	}
+	if v, ok := doc["errored"].(bool); ok {
+		p.Errored = v
+	}
+
+	backendType := localBackendType
+	if block, err := schemas.Backend("local"); err == nil {
+		backendType = block.ImpliedType()
+	}
+	config, err := ctymsgpack.Marshal(cty.NullVal(backendType), backendType)
+	if err != nil {
+		return nil, err
+	}
+	p.Backend = &plan.Backend{Type: "local", Config: &plan.DynamicValue{Msgpack: config}, Workspace: "default"}
+
+	variables := asMap(doc["variables"])
+	for _, name := range mapkeys.Sorted(variables) {
+		d, _, err := dynamicValueFromJSON(asMap(variables[name])["value"], nil, cty.DynamicPseudoType)
+		if err != nil {
+			return nil, pathError(formatDocumentKey([]string{"variables", name}), err)
+		}
+		p.Variables[name] = d
+	}
+
+	for _, section := range []string{"resource_changes", "resource_drift"} {
+		for _, rc := range asSlice(doc[section]) {
+			c, err := resourceChangeFromJSON(schemas, doc, asMap(rc))
+			if err != nil {
+				return nil, err
+			}
+
+			if section == "resource_changes" {
+				p.ResourceChanges = append(p.ResourceChanges, c)
+			} else {
+				p.ResourceDrift = append(p.ResourceDrift, c)
+			}
+		}
+	}
+
+	for _, dc := range asSlice(doc["deferred_changes"]) {
+		d := asMap(dc)
+		c, err := resourceChangeFromJSON(schemas, doc, asMap(d["resource_change"]))
+		if err != nil {
+			return nil, err
+		}
+
+		reason, ok := plan.DeferredReason_value[strings.ToUpper(asString(d["reason"]))]
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported deferred reason %q", c.GetAddr(), d["reason"])
+		}
+		p.DeferredChanges = append(p.DeferredChanges, &plan.DeferredResourceInstanceChange{
+			Deferred: &plan.Deferred{Reason: plan.DeferredReason(reason)},
+			Change:   c,
+		})
+	}
+
+	outputs := asMap(doc["output_changes"])
+	for _, name := range mapkeys.Sorted(outputs) {
+		c := asMap(outputs[name])
+		change, err := changeFromJSON(c, cty.DynamicPseudoType)
+		if err != nil {
+			return nil, pathError(formatDocumentKey([]string{"output_changes", name}), err)
+		}
+
+		p.OutputChanges = append(p.OutputChanges, &plan.OutputChange{
+			Name:      name,
+			Change:    change,
+			Sensitive: c["before_sensitive"] == true || c["after_sensitive"] == true,
+		})
+	}
+
+	for _, ra := range asSlice(doc["relevant_attributes"]) {
+		ra := asMap(ra)
+		attr, err := pathFromJSON(asSlice(ra["attribute"]), cty.DynamicPseudoType)
+		if err != nil {
+			return nil, fmt.Errorf("relevant attribute of %s: %w", ra["resource"], err)
+		}
+		path, err := pathToProto(attr)
+		if err != nil {
+			return nil, err
+		}
+		p.RelevantAttributes = append(p.RelevantAttributes, &plan.PlanResourceAttr{
+			Resource: asString(ra["resource"]),
+			Attr:     path,
+		})
+	}
+
+	return p, nil
+}
+
+// resourceChangeFromJSON converts a resource change in the JSON plan, encoding its values with
+// the type from the resource's schema.
+func resourceChangeFromJSON(schemas *schema.Schemas, doc map[string]any, rc map[string]any) (*plan.ResourceInstanceChange, error) {
+	key := jsonResourceAddress(rc)
+	addr, err := addrs.ParseResourceInstance(asString(rc["address"]))
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig, err := jsonProviderConfig(doc, addr, asString(rc["provider_name"]))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	block, err := resourceSchema(schemas, addr.String(), providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	ty := block.ImpliedType()
+
+	c := asMap(rc["change"])
+	change, err := changeFromJSON(c, ty)
+	if err != nil {
+		return nil, pathError(key, err)
+	}
+
+	if importing := asMap(c["importing"]); importing != nil {
+		change.Importing = &plan.Importing{
+			Id:      asString(importing["id"]),
+			Unknown: importing["unknown"] == true,
+		}
+	}
+	change.GeneratedConfig = asString(c["generated_config"])
+
+	ric := &plan.ResourceInstanceChange{
+		Addr:        addr.String(),
+		PrevRunAddr: asString(rc["previous_address"]),
+		DeposedKey:  asString(rc["deposed"]),
+		Provider:    providerConfig,
+		Change:      change,
+	}
+
+	if reason := asString(rc["action_reason"]); reason != "" {
+		r, ok := plan.ResourceInstanceActionReason_value[strings.ToUpper(reason)]
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported action reason %q", key, reason)
+		}
+		ric.ActionReason = plan.ResourceInstanceActionReason(r)
+	}
+
+	for _, rp := range asSlice(c["replace_paths"]) {
+		replace, err := pathFromJSON(asSlice(rp), ty)
+		if err != nil {
+			return nil, fmt.Errorf("%s: replace path: %w", key, err)
+		}
+		path, err := pathToProto(replace)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		ric.RequiredReplace = append(ric.RequiredReplace, path)
+	}
+
+	return ric, nil
+}
+
+// changeFromJSON converts the action, values and sensitivity of a change in the JSON plan. The
+// values are laid out the same way as Terraform does for each action.
+func changeFromJSON(c map[string]any, ty cty.Type) (*plan.Change, error) {
+	actions := []string{}
+	for _, a := range asSlice(c["actions"]) {
+		actions = append(actions, asString(a))
+	}
+	action, ok := jsonActions[strings.Join(actions, ",")]
+	if !ok {
+		return nil, fmt.Errorf("unsupported actions %q", actions)
+	}
+
+	before, beforeVal, err := dynamicValueFromJSON(c["before"], nil, ty)
+	if err != nil {
+		return nil, err
+	}
+	after, afterVal, err := dynamicValueFromJSON(c["after"], c["after_unknown"], ty)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &plan.Change{Action: action}
+	switch action {
+	case plan.Action_CREATE:
+		change.Values = []*plan.DynamicValue{after}
+	case plan.Action_DELETE, plan.Action_NOOP, plan.Action_FORGET:
+		change.Values = []*plan.DynamicValue{before}
+	default:
+		change.Values = []*plan.DynamicValue{before, after}
+	}
+
+	if action != plan.Action_CREATE {
+		change.BeforeSensitivePaths, err = sensitivePathsFromJSON(beforeVal, c["before_sensitive"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if action != plan.Action_DELETE {
+		change.AfterSensitivePaths, err = sensitivePathsFromJSON(afterVal, c["after_sensitive"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return change, nil
+}
+
+// dynamicValueFromJSON converts a value in the JSON plan to a value of the type, marking the
+// elements that are unknown according to the mask, and encodes it as a dynamic value.
+func dynamicValueFromJSON(v any, unknown any, ty cty.Type) (*plan.DynamicValue, cty.Value, error) {
+	doc, err := editingValueFromJSON(v, unknown, ty)
+	if err != nil {
+		return nil, cty.NilVal, err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, cty.NilVal, err
+	}
+
+	val, err := unmarshalValueJSON(b, ty)
+	if err != nil {
+		return nil, cty.NilVal, err
+	}
+
+	mp, err := ctymsgpack.Marshal(val, ty)
+	if err != nil {
+		return nil, cty.NilVal, err
+	}
+
+	return &plan.DynamicValue{Msgpack: mp}, val, nil
+}
+
+// editingValueFromJSON converts a value in the JSON plan to the JSON we use in editing documents,
+// which marks unknown values and records the type of dynamically typed values. The JSON plan
+// omits unknown attributes, so they're added back from the mask.
+func editingValueFromJSON(v any, unknown any, ty cty.Type) (any, error) {
+	if unknown == true {
+		return map[string]bool{unknownValueMarker: true}, nil
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch {
+	case ty == cty.DynamicPseudoType:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		vty, err := ctyjson.ImpliedType(b)
+		if err != nil {
+			return nil, err
+		}
+		typeJSON, err := ctyjson.MarshalType(vty)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"value": escapeJSONKeys(v), "type": json.RawMessage(typeJSON)}, nil
+	case ty.IsObjectType():
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+
+		out := map[string]any{}
+		for name, attr := range m {
+			out[escapeValueKey(name)] = attr
+		}
+		for name, aty := range ty.AttributeTypes() {
+			attr, ok := m[name]
+			u := asMap(unknown)[name]
+			if !ok && u != true {
+				continue
+			}
+
+			var err error
+			out[escapeValueKey(name)], err = editingValueFromJSON(attr, u, aty)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return out, nil
+	case ty.IsMapType():
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+
+		out := map[string]any{}
+		for k, elem := range m {
+			var err error
+			out[escapeValueKey(k)], err = editingValueFromJSON(elem, asMap(unknown)[k], ty.ElementType())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return out, nil
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		l, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+
+		u := asSlice(unknown)
+		out := make([]any, len(l))
+		for i, elem := range l {
+			ety := cty.DynamicPseudoType
+			switch {
+			case ty.IsTupleType() && i < ty.Length():
+				ety = ty.TupleElementType(i)
+			case !ty.IsTupleType():
+				ety = ty.ElementType()
+			}
+
+			var eu any
+			if i < len(u) {
+				eu = u[i]
+			}
+
+			var err error
+			out[i], err = editingValueFromJSON(elem, eu, ety)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// escapeJSONKeys escapes the keys of every object in the decoded JSON with escapeValueKey.
+func escapeJSONKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := map[string]any{}
+		for k, elem := range v {
+			out[escapeValueKey(k)] = escapeJSONKeys(elem)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = escapeJSONKeys(elem)
+		}
+
+		return out
+	default:
+		return v
+	}
+}
+
+// sensitivePathsFromJSON converts the sensitivity of a value in the JSON plan to the paths of its
+// sensitive elements. Elements of sets can't be addressed, so the whole set is sensitive if any
+// of its elements are.
+func sensitivePathsFromJSON(val cty.Value, sensitive any) ([]*plan.Path, error) {
+	paths := []*plan.Path{}
+	for _, path := range sensitiveMaskPaths(val.Type(), sensitive, cty.Path{}) {
+		pp, err := pathToProto(path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, pp)
+	}
+
+	return paths, nil
+}
+
+func sensitiveMaskPaths(ty cty.Type, sensitive any, path cty.Path) []cty.Path {
+	if sensitive == true {
+		return []cty.Path{path.Copy()}
+	}
+
+	paths := []cty.Path{}
+	switch s := sensitive.(type) {
+	case map[string]any:
+		for _, k := range mapkeys.Sorted(s) {
+			switch {
+			case ty.IsObjectType() && ty.HasAttribute(k):
+				paths = append(paths, sensitiveMaskPaths(ty.AttributeType(k), s[k], path.GetAttr(k))...)
+			case ty.IsMapType():
+				paths = append(paths, sensitiveMaskPaths(ty.ElementType(), s[k], path.Index(cty.StringVal(k)))...)
+			}
+		}
+	case []any:
+		for i, elem := range s {
+			switch {
+			case ty.IsSetType():
+				if len(sensitiveMaskPaths(ty.ElementType(), elem, nil)) > 0 {
+					return []cty.Path{path.Copy()}
+				}
+			case ty.IsListType():
+				paths = append(paths, sensitiveMaskPaths(ty.ElementType(), elem, path.Index(cty.NumberIntVal(int64(i))))...)
+			case ty.IsTupleType() && i < ty.Length():
+				paths = append(paths, sensitiveMaskPaths(ty.TupleElementType(i), elem, path.Index(cty.NumberIntVal(int64(i))))...)
+			}
+		}
+	}
+
+	return paths
+}
+
+// pathFromJSON converts a path in the JSON plan, such as a replace path, to a cty path. Strings
+// are attribute names unless the type says they're map keys.
+func pathFromJSON(steps []any, ty cty.Type) (cty.Path, error) {
+	path := cty.Path{}
+	for _, step := range steps {
+		switch s := step.(type) {
+		case string:
+			switch {
+			case ty.IsMapType():
+				path = path.Index(cty.StringVal(s))
+				ty = ty.ElementType()
+			case ty.IsObjectType() && ty.HasAttribute(s):
+				path = path.GetAttr(s)
+				ty = ty.AttributeType(s)
+			default:
+				path = path.GetAttr(s)
+				ty = cty.DynamicPseudoType
+			}
+		case json.Number:
+			key, err := cty.ParseNumberVal(s.String())
+			if err != nil {
+				return nil, fmt.Errorf("invalid path step %s: %w", s, err)
+			}
+			path = path.Index(key)
+			switch {
+			case ty.IsListType(), ty.IsSetType():
+				ty = ty.ElementType()
+			default:
+				ty = cty.DynamicPseudoType
+			}
+		default:
+			return nil, fmt.Errorf("invalid path step %v", step)
+		}
+	}
+
+	return path, nil
+}
+
+// jsonProviderConfig returns the address of the provider configuration of the resource. The JSON
+// plan only records the provider's source address, so the module and alias of the configuration
+// are found through the provider_config_key of the resource's configuration, which refers to the
+// configuration that declares the provider, e.g. module.child:aws.west.
+func jsonProviderConfig(doc map[string]any, addr addrs.Address, providerName string) (string, error) {
+	provider, err := addrs.ParseProvider(providerName)
+	if err != nil {
+		return "", err
+	}
+	pc := addrs.ProviderConfig{Provider: provider}
+
+	config := asMap(doc["configuration"])
+	module := asMap(config["root_module"])
+	for _, step := range addr.Module {
+		module = asMap(asMap(asMap(module["module_calls"])[step.Name])["module"])
+	}
+	for _, r := range asSlice(module["resources"]) {
+		r := asMap(r)
+		if r["address"] != addr.Resource.String() {
+			continue
+		}
+
+		key := asString(r["provider_config_key"])
+		moduleAddr, name := "", key
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			moduleAddr, name = key[:i], key[i+1:]
+		}
+		if _, alias, ok := strings.Cut(name, "."); ok {
+			pc.Alias = alias
+		}
+		if p, ok := asMap(config["provider_config"])[key].(map[string]any); ok {
+			moduleAddr = asString(p["module_address"])
+			pc.Alias = asString(p["alias"])
+		}
+
+		if moduleAddr != "" {
+			m, err := addrs.Parse(moduleAddr)
+			if err != nil || m.Resource != nil {
+				return "", fmt.Errorf("invalid module address %q of provider configuration %s", moduleAddr, key)
+			}
+			pc.Module = m.Module
+		}
+	}
+
+	return pc.String(), nil
+}
+
+// statesFromJSON converts the prior state of the JSON plan to state snapshots. The prior state is
+// the state after refresh, so the previous run state has the values from before any drift.
+func statesFromJSON(schemas *schema.Schemas, doc map[string]any) (*state.State, *state.State, error) {
+	lineage, err := state.NewLineage()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prior, err := stateFromJSON(schemas, doc, lineage)
+	if err != nil {
+		return nil, nil, err
+	}
+	prev, err := stateFromJSON(schemas, doc, lineage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, rc := range asSlice(doc["resource_drift"]) {
+		rc := asMap(rc)
+		before := asMap(rc["change"])["before"]
+		if before == nil {
+			continue
+		}
+
+		if err = setStateInstance(schemas, doc, prev, rc, before, asMap(rc["change"])["before_sensitive"]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return prior, prev, nil
+}
+
+func stateFromJSON(schemas *schema.Schemas, doc map[string]any, lineage string) (*state.State, error) {
+	priorState := asMap(doc["prior_state"])
+	values := asMap(priorState["values"])
+
+	s := &state.State{
+		Version:          state.Version,
+		TerraformVersion: asString(doc["terraform_version"]),
+		Serial:           1,
+		Lineage:          lineage,
+		Outputs:          map[string]*state.Output{},
+		Resources:        []*state.Resource{},
+	}
+
+	outputs := asMap(values["outputs"])
+	for _, name := range mapkeys.Sorted(outputs) {
+		o := asMap(outputs[name])
+		value, err := json.Marshal(o["value"])
+		if err != nil {
+			return nil, err
+		}
+
+		typeJSON, err := json.Marshal(o["type"])
+		if err != nil {
+			return nil, err
+		}
+		if o["type"] == nil {
+			ty, err := ctyjson.ImpliedType(value)
+			if err != nil {
+				return nil, fmt.Errorf("output %s: %w", name, err)
+			}
+			typeJSON, err = ctyjson.MarshalType(ty)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		s.Outputs[name] = &state.Output{Value: value, Type: typeJSON, Sensitive: o["sensitive"] == true}
+	}
+
+	var module func(m map[string]any) error
+	module = func(m map[string]any) error {
+		for _, r := range asSlice(m["resources"]) {
+			r := asMap(r)
+			if err := setStateInstance(schemas, doc, s, r, r["values"], r["sensitive_values"]); err != nil {
+				return err
+			}
+		}
+		for _, child := range asSlice(m["child_modules"]) {
+			if err := module(asMap(child)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := module(asMap(values["root_module"])); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// setStateInstance sets the attributes of the resource instance described by the JSON resource,
+// adding the instance and its resource to the state if they're not already there.
+func setStateInstance(schemas *schema.Schemas, doc map[string]any, s *state.State, r map[string]any, values any, sensitive any) error {
+	key := jsonResourceAddress(r)
+	addr, err := addrs.ParseResourceInstance(asString(r["address"]))
+	if err != nil {
+		return err
+	}
+
+	providerConfig, err := jsonProviderConfig(doc, addr, asString(r["provider_name"]))
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	block, err := resourceSchema(schemas, addr.String(), providerConfig)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	raw, err := editingValueFromJSON(values, nil, block.ImpliedType())
+	if err != nil {
+		return pathError(key, err)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	val, err := unmarshalValueJSON(b, block.ImpliedType())
+	if err != nil {
+		return pathError(key, err)
+	}
+
+	attributes, err := ctyjson.Marshal(val, block.ImpliedType())
+	if err != nil {
+		return pathError(key, err)
+	}
+	sensitivePaths, err := state.MarshalPaths(sensitiveMaskPaths(val.Type(), sensitive, cty.Path{}))
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	mode := "managed"
+	if addr.Resource.Mode == addrs.DataResourceMode {
+		mode = "data"
+	}
+
+	var resource *state.Resource
+	for _, sr := range s.Resources {
+		if sr.Module == addr.Module.String() && sr.Mode == mode && sr.Type == addr.Resource.Type && sr.Name == addr.Resource.Name {
+			resource = sr
+		}
+	}
+	if resource == nil {
+		resource = &state.Resource{
+			Module:   addr.Module.String(),
+			Mode:     mode,
+			Type:     addr.Resource.Type,
+			Name:     addr.Resource.Name,
+			Provider: providerConfig,
+		}
+		s.Resources = append(s.Resources, resource)
+	}
+
+	var indexKey any
+	switch k := addr.Key.(type) {
+	case addrs.IntKey:
+		indexKey = int(k)
+		resource.Each = "list"
+	case addrs.StringKey:
+		indexKey = string(k)
+		resource.Each = "map"
+	}
+
+	deposed := asString(r["deposed"])
+	var instance *state.Instance
+	for _, si := range resource.Instances {
+		if fmt.Sprint(si.IndexKey) == fmt.Sprint(indexKey) && si.Deposed == deposed {
+			instance = si
+		}
+	}
+	if instance == nil {
+		instance = &state.Instance{IndexKey: indexKey, Deposed: deposed}
+		resource.Instances = append(resource.Instances, instance)
+		sort.SliceStable(resource.Instances, func(i, j int) bool {
+			return resource.Instances[i].Deposed == "" && resource.Instances[j].Deposed != ""
+		})
+	}
+
+	if v, ok := r["schema_version"].(json.Number); ok {
+		n, err := v.Int64()
+		if err != nil {
+			return fmt.Errorf("%s: invalid schema version: %w", key, err)
+		}
+		instance.SchemaVersion = uint64(n)
+	}
+	instance.Attributes = attributes
+	instance.SensitiveAttributes = sensitivePaths
+	for _, d := range asSlice(r["depends_on"]) {
+		instance.Dependencies = append(instance.Dependencies, asString(d))
+	}
+
+	return nil
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,283 @@
+package edit
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+const testFromJSONPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.1",
+  "timestamp": "2024-07-08T17:19:28Z",
+  "applyable": true,
+  "complete": true,
+  "errored": false,
+  "variables": {"region": {"value": "us-east-1"}, "zones": {"value": ["a", "b"]}},
+  "resource_drift": [
+    {
+      "address": "aws_instance.web",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["update"],
+        "before": {"id": "i-1234", "ami": "ami-drifted", "security_groups": ["web"], "tags": {"secret": "s3cr3t"}, "root_block_device": []},
+        "after": {"id": "i-1234", "ami": "ami-old", "security_groups": ["web"], "tags": {"secret": "s3cr3t"}, "root_block_device": []},
+        "after_unknown": {},
+        "before_sensitive": {"tags": {"secret": true}},
+        "after_sensitive": {"tags": {"secret": true}}
+      }
+    }
+  ],
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"id": "i-1234", "ami": "ami-old", "security_groups": ["web"], "tags": {"secret": "s3cr3t"}, "root_block_device": []},
+        "after": {"ami": "ami-new", "security_groups": ["web"], "tags": {"secret": "s3cr3t"}, "root_block_device": [{"size": 8}]},
+        "after_unknown": {"id": true, "root_block_device": [{}]},
+        "before_sensitive": {"tags": {"secret": true}},
+        "after_sensitive": {"tags": {"secret": true}},
+        "replace_paths": [["ami"]]
+      },
+      "action_reason": "replace_because_cannot_update"
+    },
+    {
+      "address": "aws_instance.db[0]",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "db",
+      "index": 0,
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"ami": "ami-db", "security_groups": null, "tags": null, "root_block_device": []},
+        "after_unknown": {"id": true},
+        "before_sensitive": false,
+        "after_sensitive": {}
+      }
+    }
+  ],
+  "output_changes": {
+    "ip": {"actions": ["create"], "before": null, "after": null, "after_unknown": true, "before_sensitive": false, "after_sensitive": true}
+  },
+  "prior_state": {
+    "format_version": "1.0",
+    "terraform_version": "1.9.1",
+    "values": {
+      "outputs": {"ami": {"sensitive": false, "value": "ami-old", "type": "string"}},
+      "root_module": {
+        "resources": [
+          {
+            "address": "aws_instance.web",
+            "mode": "managed",
+            "type": "aws_instance",
+            "name": "web",
+            "provider_name": "registry.terraform.io/hashicorp/aws",
+            "schema_version": 1,
+            "values": {"id": "i-1234", "ami": "ami-old", "security_groups": ["web"], "tags": {"secret": "s3cr3t"}, "root_block_device": []},
+            "sensitive_values": {"tags": {"secret": true}}
+          }
+        ]
+      }
+    }
+  },
+  "configuration": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.web", "provider_config_key": "aws.west"},
+        {"address": "aws_instance.db", "provider_config_key": "aws"}
+      ]
+    }
+  }
+}`
+
+func requireZipFiles(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	reader, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	files := map[string]string{}
+	for _, f := range reader.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[f.Name] = string(b)
+	}
+
+	return files
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(testFromJSONPlan), 0o644))
+
+	configDir := filepath.Join(dir, "config")
+	for name, content := range map[string]string{
+		"main.tf":                         "module \"net\" {\n  source = \"./net\"\n}\n",
+		"README.md":                       "not configuration",
+		".terraform.lock.hcl":             "# lock\n",
+		"net/net.tf":                      "# net\n",
+		".terraform/modules/modules.json": `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"net","Source":"./net","Dir":"net"}]}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(configDir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(configDir, name), []byte(content), 0o644))
+	}
+
+	dstPath := filepath.Join(dir, "tf.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath, Schemas: testSchemas(t)}).FromJSON(configDir))
+
+	files := requireZipFiles(t, dstPath)
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{
+		".terraform.lock.hcl",
+		"tfconfig/m-/main.tf",
+		"tfconfig/m-net/net.tf",
+		"tfconfig/modules.json",
+		"tfplan",
+		"tfstate",
+		"tfstate-prev",
+	}, names)
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	require.Equal(t, "1.9.1", p.GetTerraformVersion())
+	require.Equal(t, "2024-07-08T17:19:28Z", p.GetTimestamp())
+	require.True(t, p.GetApplyable())
+	require.Equal(t, "local", p.GetBackend().GetType())
+
+	zones, err := ctymsgpack.Unmarshal(p.GetVariables()["zones"].GetMsgpack(), cty.DynamicPseudoType)
+	require.NoError(t, err)
+	require.True(t, cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}).RawEquals(zones))
+
+	web := p.GetResourceChanges()[0]
+	require.Equal(t, `provider["registry.terraform.io/hashicorp/aws"].west`, web.GetProvider())
+	require.Equal(t, plan.Action_DELETE_THEN_CREATE, web.GetChange().GetAction())
+	require.Equal(t, plan.ResourceInstanceActionReason_REPLACE_BECAUSE_CANNOT_UPDATE, web.GetActionReason())
+	require.Len(t, web.GetChange().GetValues(), 2)
+
+	after, err := ctymsgpack.Unmarshal(web.GetChange().GetValues()[1].GetMsgpack(), testInstanceType)
+	require.NoError(t, err)
+	require.True(t, cty.ObjectVal(map[string]cty.Value{
+		"id":                cty.UnknownVal(cty.String),
+		"ami":               cty.StringVal("ami-new"),
+		"security_groups":   cty.SetVal([]cty.Value{cty.StringVal("web")}),
+		"tags":              cty.MapVal(map[string]cty.Value{"secret": cty.StringVal("s3cr3t")}),
+		"root_block_device": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(8)})}),
+	}).RawEquals(after))
+
+	sensitive, err := pathFromProto(web.GetChange().GetAfterSensitivePaths()[0])
+	require.NoError(t, err)
+	require.Equal(t, cty.GetAttrPath("tags").Index(cty.StringVal("secret")), sensitive)
+	replace, err := pathFromProto(web.GetRequiredReplace()[0])
+	require.NoError(t, err)
+	require.Equal(t, cty.GetAttrPath("ami"), replace)
+
+	db := p.GetResourceChanges()[1]
+	require.Equal(t, `provider["registry.terraform.io/hashicorp/aws"]`, db.GetProvider())
+	require.Equal(t, plan.Action_CREATE, db.GetChange().GetAction())
+	require.Len(t, db.GetChange().GetValues(), 1)
+	require.Empty(t, db.GetChange().GetBeforeSensitivePaths())
+
+	require.Len(t, p.GetResourceDrift(), 1)
+	require.Equal(t, "ip", p.GetOutputChanges()[0].GetName())
+	require.True(t, p.GetOutputChanges()[0].GetSensitive())
+
+	prior, err := state.Parse([]byte(files["tfstate"]))
+	require.NoError(t, err)
+	prev, err := state.Parse([]byte(files["tfstate-prev"]))
+	require.NoError(t, err)
+	require.Equal(t, prior.Lineage, prev.Lineage)
+	require.Len(t, prior.Resources, 1)
+	require.Equal(t, `provider["registry.terraform.io/hashicorp/aws"].west`, prior.Resources[0].Provider)
+	require.EqualValues(t, 1, prior.Resources[0].Instances[0].SchemaVersion)
+	require.JSONEq(t, `[[{"type":"get_attr","value":"tags"},{"type":"index","value":{"value":"secret","type":"string"}}]]`, string(prior.Resources[0].Instances[0].SensitiveAttributes))
+	require.Contains(t, string(prior.Resources[0].Instances[0].Attributes), `"ami": "ami-old"`)
+	require.Contains(t, string(prev.Resources[0].Instances[0].Attributes), `"ami": "ami-drifted"`)
+	require.JSONEq(t, `"ami-old"`, string(prior.Outputs["ami"].Value))
+}
+
+func TestFromJSONRequiresSchemas(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(testFromJSONPlan), 0o644))
+
+	err := New(&Config{PlanPath: planPath, DstPath: filepath.Join(dir, "tf.plan")}).FromJSON(dir)
+	require.EqualError(t, err, "you must provide provider schemas to rebuild a plan from JSON")
+}
+
+func TestJSONProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	doc := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "configuration": {
+    "provider_config": {
+      "aws.west": {"name": "aws", "full_name": "registry.terraform.io/hashicorp/aws", "alias": "west"},
+      "module.child:aws": {"name": "aws", "full_name": "registry.terraform.io/hashicorp/aws", "module_address": "module.child"}
+    },
+    "root_module": {
+      "resources": [{"address": "aws_instance.web", "provider_config_key": "aws.west"}],
+      "module_calls": {
+        "child": {
+          "module": {
+            "resources": [
+              {"address": "aws_instance.own", "provider_config_key": "module.child:aws"},
+              {"address": "aws_instance.inherited", "provider_config_key": "aws.west"},
+              {"address": "aws_instance.undeclared", "provider_config_key": "module.child:aws.east"}
+            ]
+          }
+        }
+      }
+    }
+  }
+}`), &doc))
+
+	for addr, expected := range map[string]string{
+		`aws_instance.web`:                       `provider["registry.terraform.io/hashicorp/aws"].west`,
+		`module.child.aws_instance.own`:          `module.child.provider["registry.terraform.io/hashicorp/aws"]`,
+		`module.child[0].aws_instance.own`:       `module.child.provider["registry.terraform.io/hashicorp/aws"]`,
+		`module.child.aws_instance.inherited`:    `provider["registry.terraform.io/hashicorp/aws"].west`,
+		`module.child.aws_instance.undeclared`:   `module.child.provider["registry.terraform.io/hashicorp/aws"].east`,
+		`module.child.aws_instance.unconfigured`: `provider["registry.terraform.io/hashicorp/aws"]`,
+	} {
+		a, err := addrs.Parse(addr)
+		require.NoError(t, err)
+		pc, err := jsonProviderConfig(doc, a, "registry.terraform.io/hashicorp/aws")
+		require.NoError(t, err)
+		require.Equal(t, expected, pc, addr)
+	}
+}
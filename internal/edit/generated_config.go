@@ -0,0 +1,161 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// generatedConfig is a reference to a Change with generated config, which is produced when
+// planning import blocks with -generate-config-out.
+type generatedConfig struct {
+	addr     string
+	provider string
+	change   *plan.Change
+}
+
+// generatedConfigs returns a reference to every change in the plan that has generated config.
+func generatedConfigs(p *plan.Plan) []generatedConfig {
+	configs := []generatedConfig{}
+
+	for _, c := range p.GetResourceChanges() {
+		if c.GetChange().GetGeneratedConfig() != "" {
+			configs = append(configs, generatedConfig{addr: c.GetAddr(), provider: c.GetProvider(), change: c.GetChange()})
+		}
+	}
+
+	for _, d := range p.GetDeferredChanges() {
+		if c := d.GetChange(); c.GetChange().GetGeneratedConfig() != "" {
+			configs = append(configs, generatedConfig{addr: c.GetAddr(), provider: c.GetProvider(), change: c.GetChange()})
+		}
+	}
+
+	return configs
+}
+
+// generatedConfigFileNames returns a unique .tf file name for each generated config, named after
+// the resource address.
+func generatedConfigFileNames(configs []generatedConfig) []string {
+	names := make([]string, len(configs))
+	seen := map[string]int{}
+
+	for i, c := range configs {
+		name := strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+				return r
+			default:
+				return '_'
+			}
+		}, c.addr)
+
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+
+		names[i] = name + ".tf"
+	}
+
+	return names
+}
+
+// editGeneratedConfigs edits each non-empty generated config in the plan as its own .tf file. The
+// edited config must be syntactically valid HCL before it is written back into the change.
+func editGeneratedConfigs(path string, config *Config, p *plan.Plan) error {
+	configs := generatedConfigs(p)
+	names := generatedConfigFileNames(configs)
+
+	for i, c := range configs {
+		tmpFilePath := filepath.Join(filepath.Dir(path), "generated-config-"+names[i])
+		tmpFile, err := os.Create(tmpFilePath)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmpFilePath)
+
+		_, err = tmpFile.WriteString(c.change.GetGeneratedConfig())
+		if err != nil {
+			return err
+		}
+		tmpFile.Close()
+
+		err = editFile(config.TextEditorCmd, tmpFilePath)
+		if err != nil {
+			return err
+		}
+
+		tmpFile, err = os.Open(tmpFilePath)
+		if err != nil {
+			return err
+		}
+		defer tmpFile.Close()
+
+		bytes, err := io.ReadAll(tmpFile)
+		if err != nil {
+			return err
+		}
+
+		if err = validateHCLSyntax(filepath.Base(tmpFilePath), bytes); err != nil {
+			return fmt.Errorf("invalid generated config for %s: %w", c.addr, err)
+		}
+
+		c.change.GeneratedConfig = string(bytes)
+	}
+
+	return nil
+}
+
+// validateHCLSyntax returns an error describing every syntax error in the HCL source.
+func validateHCLSyntax(filename string, src []byte) error {
+	_, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if !diags.HasErrors() {
+		return nil
+	}
+
+	errs := []error{}
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError {
+			errs = append(errs, errors.New(diag.Error()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ExportGeneratedConfig writes every generated config in the plan to a .tf file in the directory,
+// named after the resource address.
+func (e *Editor) ExportGeneratedConfig(dir string) error {
+	p, err := e.readPlan()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0o770); err != nil {
+		return err
+	}
+
+	configs := generatedConfigs(p)
+	names := generatedConfigFileNames(configs)
+	for i, c := range configs {
+		path := filepath.Join(dir, names[i])
+		fmt.Println("write: " + path)
+		if err = os.WriteFile(path, []byte(c.change.GetGeneratedConfig()), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if len(configs) == 0 {
+		fmt.Println("no generated config found in plan")
+	}
+
+	return nil
+}
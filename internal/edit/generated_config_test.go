@@ -0,0 +1,136 @@
+package edit
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// requirePlanFile writes a Terraform plan zip that contains the tfplan and any other files.
+func requirePlanFile(t *testing.T, p *plan.Plan, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tf.plan")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	archive := zip.NewWriter(f)
+	b, err := proto.Marshal(p)
+	require.NoError(t, err)
+	w, err := archive.Create("tfplan")
+	require.NoError(t, err)
+	_, err = w.Write(b)
+	require.NoError(t, err)
+
+	for name, content := range files {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+
+	return path
+}
+
+func testGeneratedConfigPlan() *plan.Plan {
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr: `aws_instance.web["a"]`,
+				Change: &plan.Change{
+					Action:          plan.Action_NOOP,
+					Importing:       &plan.Importing{Id: "i-1234"},
+					GeneratedConfig: "resource \"aws_instance\" \"web\" {\n  password = \"secret\"\n}\n",
+				},
+			},
+			{
+				Addr:   "aws_instance.db",
+				Change: &plan.Change{Action: plan.Action_CREATE},
+			},
+		},
+		DeferredChanges: []*plan.DeferredResourceInstanceChange{
+			{
+				Deferred: &plan.Deferred{Reason: plan.DeferredReason_PROVIDER_CONFIG_UNKNOWN},
+				Change: &plan.ResourceInstanceChange{
+					Addr: `module.db.aws_db_instance.db`,
+					Change: &plan.Change{
+						Action:          plan.Action_NOOP,
+						Importing:       &plan.Importing{Unknown: true},
+						GeneratedConfig: "resource \"aws_db_instance\" \"db\" {\n  password = \"secret\"\n}\n",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGeneratedConfigFileNames(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{
+		"aws_instance.web__a__.tf",
+		"aws_instance.web__a___2.tf",
+		"module.db.aws_db_instance.db.tf",
+	}, generatedConfigFileNames([]generatedConfig{
+		{addr: `aws_instance.web["a"]`},
+		{addr: `aws_instance.web['a']`},
+		{addr: `module.db.aws_db_instance.db`},
+	}))
+}
+
+func TestEditGeneratedConfigs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tfplan")
+	p := testGeneratedConfigPlan()
+
+	// The generated config is removed from the protojson document and restored when combining.
+	sans, err := editTFPlanNoMsgPack(path, &Config{TextEditorCmd: "true"}, p)
+	require.NoError(t, err)
+	require.Empty(t, sans.GetResourceChanges()[0].GetChange().GetGeneratedConfig())
+	require.Empty(t, sans.GetDeferredChanges()[0].GetChange().GetChange().GetGeneratedConfig())
+	np, err := combinePlans(sans, p)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, np))
+
+	require.NoError(t, editGeneratedConfigs(path, &Config{TextEditorCmd: sedEditor(t, `s/secret/redacted/`)}, np))
+	require.Equal(t,
+		"resource \"aws_instance\" \"web\" {\n  password = \"redacted\"\n}\n",
+		np.GetResourceChanges()[0].GetChange().GetGeneratedConfig(),
+	)
+	require.Empty(t, np.GetResourceChanges()[1].GetChange().GetGeneratedConfig())
+	require.Equal(t,
+		"resource \"aws_db_instance\" \"db\" {\n  password = \"redacted\"\n}\n",
+		np.GetDeferredChanges()[0].GetChange().GetChange().GetGeneratedConfig(),
+	)
+
+	err = editGeneratedConfigs(path, &Config{TextEditorCmd: sedEditor(t, `s/password/password{/`)}, np)
+	require.ErrorContains(t, err, `invalid generated config for aws_instance.web["a"]`)
+	require.ErrorContains(t, err, "generated-config-aws_instance.web__a__.tf:2")
+}
+
+func TestExportGeneratedConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "generated")
+	planPath := requirePlanFile(t, testGeneratedConfigPlan(), nil)
+	require.NoError(t, New(&Config{PlanPath: planPath}).ExportGeneratedConfig(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	b, err := os.ReadFile(filepath.Join(dir, "module.db.aws_db_instance.db.tf"))
+	require.NoError(t, err)
+	require.Equal(t, "resource \"aws_db_instance\" \"db\" {\n  password = \"secret\"\n}\n", string(b))
+}
@@ -0,0 +1,233 @@
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+const hexDumpBytesPerLine = 16
+
+// hexDump renders msgpack bytes as an xxd style text document that can be edited with a regular
+// text editor and turned back into bytes with parseHexDump. The document starts with an annotated
+// dump of the msgpack structure as comments to help make sense of the bytes. Each line of the
+// dump has an offset column, the hex bytes in groups of two, and an ASCII gutter.
+func hexDump(b []byte, desc string) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# %s\n", desc)
+	buf.WriteString("# Edit the hex bytes below. The offset column, the ASCII gutter and lines starting with #\n")
+	buf.WriteString("# are ignored. Bytes may be added or removed as long as the result is valid msgpack.\n")
+	buf.WriteString("#\n")
+
+	structure, err := annotateMsgpack(b)
+	if err != nil {
+		fmt.Fprintf(buf, "# unable to decode msgpack structure: %s\n", err)
+	}
+	for _, line := range structure {
+		buf.WriteString("# " + line + "\n")
+	}
+	buf.WriteString("\n")
+
+	for off := 0; off < len(b); off += hexDumpBytesPerLine {
+		line := b[off:min(off+hexDumpBytesPerLine, len(b))]
+
+		fmt.Fprintf(buf, "%08x: ", off)
+		for i := range hexDumpBytesPerLine {
+			switch {
+			case i < len(line):
+				buf.WriteString(hex.EncodeToString(line[i : i+1]))
+			default:
+				buf.WriteString("  ")
+			}
+			if i%2 == 1 {
+				buf.WriteString(" ")
+			}
+		}
+
+		buf.WriteString(" ")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			buf.WriteByte(c)
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+// parseHexDump parses a document that was rendered with hexDump back into bytes. Offsets are
+// ignored and the bytes of each line are concatenated in order. The hex bytes of a line end at the
+// first run of two spaces, which separates them from the ASCII gutter.
+func parseHexDump(doc []byte) ([]byte, error) {
+	out := []byte{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(doc))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if offset, rest, ok := strings.Cut(line, ":"); ok {
+			if _, err := strconv.ParseUint(offset, 16, 64); err == nil {
+				line = rest
+			}
+		}
+		line = strings.TrimLeft(line, " ")
+		if i := strings.Index(line, "  "); i >= 0 {
+			line = line[:i]
+		}
+
+		hexBytes := strings.ReplaceAll(line, " ", "")
+		b, err := hex.DecodeString(hexBytes)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid hex bytes %q: %w", lineNo, line, err)
+		}
+		out = append(out, b...)
+	}
+
+	return out, scanner.Err()
+}
+
+// annotateMsgpack returns a human readable description of each msgpack value in the bytes, one
+// line for each value with its offset, indented by how deeply it is nested. It returns an error
+// if the bytes are not a single valid msgpack value.
+func annotateMsgpack(b []byte) ([]string, error) {
+	r := bytes.NewReader(b)
+	dec := msgpack.NewDecoder(r)
+	lines := []string{}
+
+	if err := annotateMsgpackDec(dec, r, len(b), 0, &lines); err != nil {
+		return lines, err
+	}
+
+	if r.Len() != 0 {
+		return lines, fmt.Errorf("%d extra bytes after msgpack value", r.Len())
+	}
+
+	return lines, nil
+}
+
+func annotateMsgpackDec(dec *msgpack.Decoder, r *bytes.Reader, size int, depth int, lines *[]string) error {
+	offset := size - r.Len()
+	annotate := func(format string, args ...any) {
+		*lines = append(*lines, fmt.Sprintf("%08x  %s", offset, strings.Repeat("  ", depth))+fmt.Sprintf(format, args...))
+	}
+
+	code, err := dec.PeekCode()
+	if err != nil {
+		return fmt.Errorf("offset %08x: %w", offset, err)
+	}
+
+	switch {
+	case code == msgpcode.Nil:
+		annotate("nil")
+		return dec.Skip()
+	case code == msgpcode.True || code == msgpcode.False:
+		v, err := dec.DecodeBool()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		annotate("bool %t", v)
+		return nil
+	case msgpcode.IsString(code):
+		v, err := dec.DecodeString()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		annotate("str %s", strconv.Quote(truncate(v, 64)))
+		return nil
+	case isBin(code):
+		v, err := dec.DecodeBytes()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		annotate("bin (%d bytes) %s", len(v), strconv.Quote(truncate(string(v), 64)))
+		return nil
+	case msgpcode.IsFixedMap(code), code == msgpcode.Map16, code == msgpcode.Map32:
+		l, err := dec.DecodeMapLen()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		annotate("map (%d entries)", l)
+		for range l {
+			if err := annotateMsgpackDec(dec, r, size, depth+1, lines); err != nil {
+				return err
+			}
+			if err := annotateMsgpackDec(dec, r, size, depth+2, lines); err != nil {
+				return err
+			}
+		}
+		return nil
+	case msgpcode.IsFixedArray(code), code == msgpcode.Array16, code == msgpcode.Array32:
+		l, err := dec.DecodeArrayLen()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		annotate("array (%d elements)", l)
+		for range l {
+			if err := annotateMsgpackDec(dec, r, size, depth+1, lines); err != nil {
+				return err
+			}
+		}
+		return nil
+	case msgpcode.IsExt(code):
+		typ, l, err := dec.DecodeExtHeader()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		body := make([]byte, l)
+		if _, err := io.ReadFull(dec.Buffered(), body); err != nil {
+			return fmt.Errorf("offset %08x: failed to read extension body: %w", offset, err)
+		}
+		switch {
+		case l <= 1:
+			annotate("ext %d (%d bytes) unknown value", typ, l)
+		case typ == 0x0c:
+			annotate("ext %d (%d bytes) unknown value with refinements", typ, l)
+		default:
+			annotate("ext %d (%d bytes)", typ, l)
+		}
+		return nil
+	default:
+		v, err := dec.DecodeInterfaceLoose()
+		if err != nil {
+			return fmt.Errorf("offset %08x: %w", offset, err)
+		}
+		switch v.(type) {
+		case int64, uint64, float32, float64:
+			annotate("number %v", v)
+		default:
+			return fmt.Errorf("offset %08x: unsupported msgpack code %#x", offset, code)
+		}
+		return nil
+	}
+}
+
+// validateMsgpack returns an error if the bytes are not a single valid msgpack value.
+func validateMsgpack(b []byte) error {
+	if len(b) == 0 {
+		return errors.New("no msgpack data")
+	}
+
+	_, err := annotateMsgpack(b)
+	return err
+}
+
+func truncate(s string, l int) string {
+	if len(s) <= l {
+		return s
+	}
+
+	return s[:l] + "..."
+}
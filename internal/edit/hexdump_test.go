@@ -0,0 +1,96 @@
+package edit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+)
+
+func TestHexDumpRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in, err := ctymsgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("hunter2 is my very secret password"),
+		"config":   cty.StringVal("value"),
+		"id":       cty.UnknownVal(cty.String),
+		"port":     cty.NumberIntVal(8200),
+	}), cty.DynamicPseudoType)
+	require.NoError(t, err)
+
+	doc := hexDump(in, "resource_change_vault_0")
+	require.Contains(t, string(doc), "# resource_change_vault_0\n")
+	require.Contains(t, string(doc), `str "hunter2 is my very secret password"`)
+	require.Contains(t, string(doc), "unknown value")
+	require.Contains(t, string(doc), "number 8200")
+	require.Contains(t, string(doc), "00000000: 92")
+
+	out, err := parseHexDump(doc)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+	require.NoError(t, validateMsgpack(out))
+}
+
+func TestParseHexDump(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		doc      string
+		expected []byte
+		err      string
+	}{
+		"xxd": {
+			doc:      "# comment\n00000000: a366 6f6f  .foo\n",
+			expected: []byte{0xa3, 'f', 'o', 'o'},
+		},
+		"ignores offsets": {
+			doc:      "00000000: a3\n00000000: 666f 6f\n",
+			expected: []byte{0xa3, 'f', 'o', 'o'},
+		},
+		"no offset or gutter": {
+			doc:      "a366 6f6f\n",
+			expected: []byte{0xa3, 'f', 'o', 'o'},
+		},
+		"gutter with a colon": {
+			doc:      "a3 3a 3a 3a  .:::\n",
+			expected: []byte{0xa3, ':', ':', ':'},
+		},
+		"invalid hex": {
+			doc: "00000000: a3zz  ..\n",
+			err: "line 1",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := parseHexDump([]byte(test.doc))
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func TestValidateMsgpack(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateMsgpack([]byte{0x92, 0xc0, 0xd4, 0x00, 0x00}))
+	require.Error(t, validateMsgpack(nil))
+	require.ErrorContains(t, validateMsgpack([]byte{0xa3, 'f', 'o'}), "offset 00000000")
+	require.ErrorContains(t, validateMsgpack([]byte{0xc0, 0xc0}), "extra bytes")
+
+	lines, err := annotateMsgpack([]byte{0x81, 0xa1, 'a', 0x92, 0xc3, 0xc0})
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"00000000  map (1 entries)",
+		`00000001    str "a"`,
+		"00000003      array (2 elements)",
+		"00000004        bool true",
+		"00000005        nil",
+	}, "\n"), strings.Join(lines, "\n"))
+}
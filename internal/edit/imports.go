@@ -0,0 +1,183 @@
+package edit
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// imports returns every resource instance change in the plan that imports an object, including
+// changes that have been deferred.
+func imports(p *plan.Plan) []*plan.ResourceInstanceChange {
+	changes := []*plan.ResourceInstanceChange{}
+
+	for _, c := range p.GetResourceChanges() {
+		if c.GetChange().GetImporting() != nil {
+			changes = append(changes, c)
+		}
+	}
+
+	for _, d := range p.GetDeferredChanges() {
+		if d.GetChange().GetChange().GetImporting() != nil {
+			changes = append(changes, d.GetChange())
+		}
+	}
+
+	return changes
+}
+
+// importAt returns the change that imports the object at the address, and whether the change has
+// been deferred.
+func importAt(p *plan.Plan, addr string) (*plan.ResourceInstanceChange, bool, error) {
+	for _, c := range p.GetResourceChanges() {
+		if c.GetAddr() == addr && c.GetDeposedKey() == "" && c.GetChange().GetImporting() != nil {
+			return c, false, nil
+		}
+	}
+
+	for _, d := range p.GetDeferredChanges() {
+		c := d.GetChange()
+		if c.GetAddr() == addr && c.GetDeposedKey() == "" && c.GetChange().GetImporting() != nil {
+			return c, true, nil
+		}
+	}
+
+	return nil, false, fmt.Errorf("no import found for %s", addr)
+}
+
+// checkImport checks that the import's ID, action and values are consistent with what Terraform
+// would have planned.
+func checkImport(c *plan.ResourceInstanceChange, deferred bool) error {
+	change := c.GetChange()
+	importing := change.GetImporting()
+
+	if importing.GetUnknown() && importing.GetId() != "" {
+		return fmt.Errorf("%s: an import with an unknown ID cannot also have the ID %q", c.GetAddr(), importing.GetId())
+	}
+	if importing.GetUnknown() && !deferred {
+		return fmt.Errorf("%s: Terraform only plans imports with unknown IDs as deferred changes, defer the change first", c.GetAddr())
+	}
+
+	// An imported object always has a prior value, so a no-op carries one or both values and
+	// every other action that can import carries both.
+	values := len(change.GetValues())
+	switch change.GetAction() {
+	case plan.Action_NOOP:
+		if values == 1 || values == 2 || (deferred && values == 0) {
+			return nil
+		}
+	case plan.Action_UPDATE, plan.Action_DELETE_THEN_CREATE, plan.Action_CREATE_THEN_DELETE:
+		if values == 2 || (deferred && values == 0) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("%s: cannot import an object with a %s action", c.GetAddr(), change.GetAction())
+	}
+
+	return fmt.Errorf("%s: an import with a %s action cannot have %d values", c.GetAddr(), change.GetAction(), values)
+}
+
+// ReportImports writes a report of every import operation in the plan.
+func (e *Editor) ReportImports(w io.Writer) error {
+	p, err := e.readPlan()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tACTION\tID\tGENERATED CONFIG")
+	for _, c := range imports(p) {
+		id := c.GetChange().GetImporting().GetId()
+		if c.GetChange().GetImporting().GetUnknown() {
+			id = "(unknown)"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n",
+			c.GetAddr(),
+			c.GetChange().GetAction(),
+			id,
+			c.GetChange().GetGeneratedConfig() != "",
+		)
+	}
+
+	return tw.Flush()
+}
+
+// SetImportID sets the ID of the object that will be imported at the address.
+func (e *Editor) SetImportID(addr string, id string) error {
+	if id == "" {
+		return fmt.Errorf("you must provide an import ID for %s", addr)
+	}
+
+	return e.updatePlan(func(p *plan.Plan) error {
+		c, deferred, err := importAt(p, addr)
+		if err != nil {
+			return err
+		}
+
+		c.Change.Importing.Id = id
+		c.Change.Importing.Unknown = false
+
+		return checkImport(c, deferred)
+	})
+}
+
+// ClearImportID clears the ID of the object that will be imported at the address, which marks it
+// as unknown until apply. Terraform only plans such imports as deferred changes, so the change
+// must have been deferred.
+func (e *Editor) ClearImportID(addr string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		c, deferred, err := importAt(p, addr)
+		if err != nil {
+			return err
+		}
+
+		c.Change.Importing.Id = ""
+		c.Change.Importing.Unknown = true
+
+		return checkImport(c, deferred)
+	})
+}
+
+// ConvertImportToCreate converts the import at the address into a plain create of the object.
+// The planned new value of the import becomes the value that will be created.
+func (e *Editor) ConvertImportToCreate(addr string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		c, _, err := importAt(p, addr)
+		if err != nil {
+			return err
+		}
+
+		return convertImportToCreate(c)
+	})
+}
+
+func convertImportToCreate(c *plan.ResourceInstanceChange) error {
+	change := c.GetChange()
+
+	// A create only carries the after value, whereas every action that can import an object
+	// carries the before value first.
+	var after *plan.DynamicValue
+	switch values := change.GetValues(); {
+	case len(values) == 1 && change.GetAction() == plan.Action_NOOP:
+		after = values[0]
+	case len(values) == 2:
+		after = values[1]
+	default:
+		return fmt.Errorf("cannot convert import of %s with action %s and %d values to a create",
+			c.GetAddr(), change.GetAction(), len(values),
+		)
+	}
+
+	change.Action = plan.Action_CREATE
+	change.Values = []*plan.DynamicValue{after}
+	change.Importing = nil
+	change.BeforeSensitivePaths = nil
+	change.BeforeIdentity = nil
+	c.RequiredReplace = nil
+	c.ActionReason = plan.ResourceInstanceActionReason_NONE
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package edit
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func testImportsPlan() *plan.Plan {
+	before := &plan.DynamicValue{Msgpack: []byte("before")}
+	after := &plan.DynamicValue{Msgpack: []byte("after")}
+
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr: "aws_instance.web",
+				Change: &plan.Change{
+					Action:          plan.Action_NOOP,
+					Values:          []*plan.DynamicValue{before},
+					Importing:       &plan.Importing{Id: "i-1234"},
+					GeneratedConfig: "resource \"aws_instance\" \"web\" {}\n",
+					BeforeIdentity:  &plan.DynamicValue{Msgpack: []byte("identity")},
+					AfterIdentity:   &plan.DynamicValue{Msgpack: []byte("identity")},
+				},
+			},
+			{
+				Addr: "aws_instance.db",
+				Change: &plan.Change{
+					Action:               plan.Action_UPDATE,
+					Values:               []*plan.DynamicValue{before, after},
+					Importing:            &plan.Importing{Id: "i-5678"},
+					BeforeSensitivePaths: []*plan.Path{{}},
+					AfterSensitivePaths:  []*plan.Path{{}},
+				},
+			},
+			{
+				Addr:   "aws_instance.app",
+				Change: &plan.Change{Action: plan.Action_CREATE, Values: []*plan.DynamicValue{after}},
+			},
+		},
+		DeferredChanges: []*plan.DeferredResourceInstanceChange{
+			{
+				Change: &plan.ResourceInstanceChange{
+					Addr: "aws_instance.cache",
+					Change: &plan.Change{
+						Action: plan.Action_NOOP,
+						Importing: &plan.Importing{
+							Unknown:  true,
+							Identity: &plan.DynamicValue{Msgpack: []byte("identity")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReportImports(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	planPath := requirePlanFile(t, testImportsPlan(), nil)
+	require.NoError(t, New(&Config{PlanPath: planPath}).ReportImports(buf))
+	require.Equal(t, `ADDRESS             ACTION  ID         GENERATED CONFIG
+aws_instance.web    NOOP    i-1234     true
+aws_instance.db     UPDATE  i-5678     false
+aws_instance.cache  NOOP    (unknown)  false
+`, buf.String())
+}
+
+func TestUpdateImports(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testImportsPlan(), nil)
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	editor := New(&Config{PlanPath: planPath, DstPath: dstPath})
+
+	require.NoError(t, editor.SetImportID("aws_instance.web", "i-0000"))
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	require.Equal(t, "i-0000", p.GetResourceChanges()[0].GetChange().GetImporting().GetId())
+	require.Equal(t, plan.Action_NOOP, p.GetResourceChanges()[0].GetChange().GetAction())
+
+	require.ErrorContains(t, editor.ClearImportID("aws_instance.web"),
+		"aws_instance.web: Terraform only plans imports with unknown IDs as deferred changes, defer the change first",
+	)
+
+	editor = New(&Config{PlanPath: dstPath, DstPath: dstPath})
+	require.NoError(t, editor.SetImportID("aws_instance.cache", "c-1234"))
+	p, err = New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	importing := p.GetDeferredChanges()[0].GetChange().GetChange().GetImporting()
+	require.Equal(t, "c-1234", importing.GetId())
+	require.False(t, importing.GetUnknown())
+	require.Equal(t, []byte("identity"), importing.GetIdentity().GetMsgpack())
+
+	require.NoError(t, editor.ClearImportID("aws_instance.cache"))
+	p, err = New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	importing = p.GetDeferredChanges()[0].GetChange().GetChange().GetImporting()
+	require.True(t, importing.GetUnknown())
+	require.Empty(t, importing.GetId())
+	require.Equal(t, []byte("identity"), importing.GetIdentity().GetMsgpack())
+
+	require.ErrorContains(t, editor.SetImportID("aws_instance.app", "i-0000"), "no import found for aws_instance.app")
+	require.ErrorContains(t, editor.SetImportID("aws_instance.web", ""), "you must provide an import ID")
+}
+
+func TestConvertImportToCreate(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testImportsPlan(), nil)
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	editor := New(&Config{PlanPath: planPath, DstPath: dstPath})
+
+	for addr, expected := range map[string]string{
+		"aws_instance.web": "before",
+		"aws_instance.db":  "after",
+	} {
+		require.NoError(t, editor.ConvertImportToCreate(addr))
+		p, err := New(&Config{PlanPath: dstPath}).readPlan()
+		require.NoError(t, err)
+
+		c, _, err := importAt(p, addr)
+		require.Nil(t, c)
+		require.Error(t, err)
+
+		for _, c := range p.GetResourceChanges() {
+			if c.GetAddr() != addr {
+				continue
+			}
+
+			require.Equal(t, plan.Action_CREATE, c.GetChange().GetAction())
+			require.Nil(t, c.GetChange().GetImporting())
+			require.Empty(t, c.GetChange().GetBeforeSensitivePaths())
+			require.Nil(t, c.GetChange().GetBeforeIdentity())
+			require.Len(t, c.GetChange().GetValues(), 1)
+			require.Equal(t, expected, string(c.GetChange().GetValues()[0].GetMsgpack()))
+			if addr == "aws_instance.web" {
+				require.Equal(t, "identity", string(c.GetChange().GetAfterIdentity().GetMsgpack()))
+			}
+		}
+	}
+
+	require.ErrorContains(t, editor.ConvertImportToCreate("aws_instance.cache"), "with action NOOP and 0 values")
+}
+
+func TestCheckImport(t *testing.T) {
+	t.Parallel()
+
+	value := &plan.DynamicValue{Msgpack: []byte("value")}
+	for desc, test := range map[string]struct {
+		change   *plan.Change
+		deferred bool
+		err      string
+	}{
+		"noop": {
+			change: &plan.Change{Action: plan.Action_NOOP, Values: []*plan.DynamicValue{value}, Importing: &plan.Importing{Id: "i-1"}},
+		},
+		"deferred unknown": {
+			change:   &plan.Change{Action: plan.Action_NOOP, Importing: &plan.Importing{Unknown: true}},
+			deferred: true,
+		},
+		"unknown not deferred": {
+			change: &plan.Change{Action: plan.Action_NOOP, Values: []*plan.DynamicValue{value}, Importing: &plan.Importing{Unknown: true}},
+			err:    "aws_instance.web: Terraform only plans imports with unknown IDs as deferred changes",
+		},
+		"unknown with id": {
+			change:   &plan.Change{Action: plan.Action_NOOP, Importing: &plan.Importing{Id: "i-1", Unknown: true}},
+			deferred: true,
+			err:      `aws_instance.web: an import with an unknown ID cannot also have the ID "i-1"`,
+		},
+		"create": {
+			change: &plan.Change{Action: plan.Action_CREATE, Values: []*plan.DynamicValue{value}, Importing: &plan.Importing{Id: "i-1"}},
+			err:    "aws_instance.web: cannot import an object with a CREATE action",
+		},
+		"update missing value": {
+			change: &plan.Change{Action: plan.Action_UPDATE, Values: []*plan.DynamicValue{value}, Importing: &plan.Importing{Id: "i-1"}},
+			err:    "aws_instance.web: an import with a UPDATE action cannot have 1 values",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkImport(&plan.ResourceInstanceChange{Addr: "aws_instance.web", Change: test.change}, test.deferred)
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package edit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+)
+
+// jsonPlanValue is a reference to a value somewhere in a JSON plan, i.e. the output of
+// `terraform show -json`. The value is the field of its parent object, which might not be set.
+type jsonPlanValue struct {
+	// keys is the location of the value in the values document, e.g.
+	// ["resource_changes", "aws_instance.web", "after"].
+	keys   []string
+	parent map[string]any
+	field  string
+	// sensitive is the sensitivity of the value: either true if the whole value is sensitive, or
+	// an object or array of the same shape as the value that marks its sensitive elements.
+	sensitive any
+	// resource is the address of the resource instance the value belongs to, if any.
+	resource string
+}
+
+func (v jsonPlanValue) key() string {
+	return formatDocumentKey(v.keys)
+}
+
+func (v jsonPlanValue) present() bool {
+	_, ok := v.parent[v.field]
+	return ok
+}
+
+// jsonPlanValues returns a reference to every value in the JSON plan that can hold configuration
+// or state: variables, the before and after values of changes, and the values of planned and
+// prior resources and outputs. References are returned for the values that changes, resources
+// and outputs can have even if they're not set.
+func jsonPlanValues(doc map[string]any) []jsonPlanValue {
+	values := []jsonPlanValue{}
+	add := func(keys []string, parent map[string]any, field string, sensitive any) {
+		if parent == nil {
+			return
+		}
+		values = append(values, jsonPlanValue{keys: keys, parent: parent, field: field, sensitive: sensitive})
+	}
+	addResource := func(keys []string, r map[string]any, parent map[string]any, field string, sensitive any) {
+		add(keys, parent, field, sensitive)
+		if parent != nil {
+			values[len(values)-1].resource, _ = r["address"].(string)
+		}
+	}
+
+	variables := asMap(doc["variables"])
+	configVariables := asMap(asMap(asMap(doc["configuration"])["root_module"])["variables"])
+	for _, name := range mapkeys.Sorted(variables) {
+		add([]string{"variables", name}, asMap(variables[name]), "value", asMap(configVariables[name])["sensitive"])
+	}
+
+	for _, section := range []string{"resource_changes", "resource_drift"} {
+		for _, rc := range asSlice(doc[section]) {
+			r := asMap(rc)
+			c := asMap(r["change"])
+			addr := jsonResourceAddress(r)
+			addResource([]string{section, addr, "before"}, r, c, "before", c["before_sensitive"])
+			addResource([]string{section, addr, "after"}, r, c, "after", c["after_sensitive"])
+		}
+	}
+
+	outputs := asMap(doc["output_changes"])
+	for _, name := range mapkeys.Sorted(outputs) {
+		c := asMap(outputs[name])
+		add([]string{"output_changes", name, "before"}, c, "before", c["before_sensitive"])
+		add([]string{"output_changes", name, "after"}, c, "after", c["after_sensitive"])
+	}
+
+	stateValues := func(section string, state map[string]any) {
+		var module func(m map[string]any)
+		module = func(m map[string]any) {
+			for _, r := range asSlice(m["resources"]) {
+				r := asMap(r)
+				addResource([]string{section, jsonResourceAddress(r), "values"}, r, r, "values", r["sensitive_values"])
+			}
+			for _, child := range asSlice(m["child_modules"]) {
+				module(asMap(child))
+			}
+		}
+		module(asMap(state["root_module"]))
+
+		outputs := asMap(state["outputs"])
+		for _, name := range mapkeys.Sorted(outputs) {
+			o := asMap(outputs[name])
+			add([]string{section, "output." + name, "value"}, o, "value", o["sensitive"])
+		}
+	}
+	stateValues("planned_values", asMap(doc["planned_values"]))
+	stateValues("prior_state", asMap(asMap(doc["prior_state"])["values"]))
+
+	return values
+}
+
+// jsonResourceAddress returns the address of the resource, qualified with the deposed key if it's
+// a deposed object.
+func jsonResourceAddress(r map[string]any) string {
+	addr, _ := r["address"].(string)
+	if deposed, ok := r["deposed"].(string); ok && deposed != "" {
+		return addr + " deposed " + deposed
+	}
+
+	return addr
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+// decodeJSONPlanValue decodes JSON from a plan, keeping numbers exactly as they are.
+func decodeJSONPlanValue(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	return v, nil
+}
+
+func readJSONPlan(path string) (map[string]any, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := decodeJSONPlanValue(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode JSON plan: %w", err)
+	}
+
+	doc := asMap(v)
+	if doc == nil {
+		return nil, errors.New("unable to decode JSON plan: expected an object")
+	}
+	if _, ok := doc["format_version"]; !ok {
+		return nil, errors.New("unable to decode JSON plan: missing format_version, expected the output of 'terraform show -json'")
+	}
+
+	return doc, nil
+}
+
+func (e *Editor) writeJSONPlan(doc map[string]any) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(e.DstPath, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Println("write: " + e.DstPath)
+
+	return nil
+}
+
+// updateJSONPlan applies the update to the JSON plan at PlanPath and writes it to DstPath.
+func (e *Editor) updateJSONPlan(update func(doc map[string]any) error) error {
+	doc, err := readJSONPlan(e.PlanPath)
+	if err != nil {
+		return err
+	}
+
+	if err = update(doc); err != nil {
+		return err
+	}
+
+	return e.writeJSONPlan(doc)
+}
+
+// editJSONPlan edits a JSON plan the same way as the tfplan of a binary plan: everything but the
+// values in one document, then each value in its own document or all of them in a single
+// document.
+func (e *Editor) editJSONPlan() error {
+	doc, err := readJSONPlan(e.PlanPath)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-plan-edit")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	values := []jsonPlanValue{}
+	edited := map[string]any{}
+	for _, v := range jsonPlanValues(doc) {
+		if !v.present() {
+			continue
+		}
+		values = append(values, v)
+		edited[v.key()] = v.parent[v.field]
+	}
+	// Remove the values only once they've all been found, as the sensitivity of some values is
+	// itself a value.
+	for _, v := range values {
+		delete(v.parent, v.field)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	b, err = editJSONDocument(dir, e.Config, "plan-sans-values", b)
+	if err != nil {
+		return err
+	}
+	v, err := decodeJSONPlanValue(b)
+	if err != nil {
+		return fmt.Errorf("invalid JSON plan document: %w", err)
+	}
+	sans := asMap(v)
+	if sans == nil {
+		return errors.New("invalid JSON plan document: expected an object")
+	}
+
+	if e.SingleDocument {
+		err = editJSONPlanValuesDocument(dir, e.Config, values, edited)
+	} else {
+		err = editJSONPlanValues(dir, e.Config, values, edited)
+	}
+	if err != nil {
+		return err
+	}
+
+	slots := map[string]jsonPlanValue{}
+	for _, s := range jsonPlanValues(sans) {
+		slots[s.key()] = s
+	}
+	for _, v := range values {
+		s, ok := slots[v.key()]
+		if !ok {
+			return fmt.Errorf("%s: the edited plan no longer has the %s the value belongs to", v.key(), v.keys[0])
+		}
+		s.parent[s.field] = edited[v.key()]
+	}
+
+	return e.writeJSONPlan(sans)
+}
+
+// editJSONPlanValues edits each value in its own document.
+func editJSONPlanValues(dir string, config *Config, values []jsonPlanValue, edited map[string]any) error {
+	for _, v := range values {
+		b, err := json.MarshalIndent(edited[v.key()], "", "  ")
+		if err != nil {
+			return err
+		}
+
+		name := "value-" + strings.NewReplacer(" ", "-", "/", "-").Replace(strings.Join(v.keys, "_"))
+		b, err = editJSONDocument(dir, config, name, b)
+		if err != nil {
+			return err
+		}
+
+		edited[v.key()], err = decodeJSONPlanValue(b)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", v.key(), err)
+		}
+	}
+
+	return nil
+}
+
+// editJSONPlanValuesDocument edits every value in a single document keyed by section, address
+// and value name.
+func editJSONPlanValuesDocument(dir string, config *Config, values []jsonPlanValue, edited map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	doc := map[string]any{}
+	for _, v := range values {
+		b, err := json.Marshal(edited[v.key()])
+		if err != nil {
+			return err
+		}
+		setDocumentValue(doc, v.keys, b)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	b, err = editJSONDocument(dir, config, "values", b)
+	if err != nil {
+		return err
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err = flattenDocument(b, doc, nil, raw); err != nil {
+		return fmt.Errorf("invalid values document: %w", err)
+	}
+
+	for _, v := range values {
+		edited[v.key()], err = decodeJSONPlanValue(raw[v.key()])
+		if err != nil {
+			return fmt.Errorf("invalid values document: %s: %w", v.key(), err)
+		}
+	}
+
+	return nil
+}
+
+// editJSONDocument writes the JSON to a document in the editing format, edits it, and returns the
+// edited document as JSON.
+func editJSONDocument(dir string, config *Config, name string, jsonBytes []byte) ([]byte, error) {
+	docBytes, err := toEditingFormat(config.Format, jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, name+formatExt(config.Format))
+	if err = os.WriteFile(path, docBytes, 0o644); err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	if err = editFile(config.TextEditorCmd, path); err != nil {
+		return nil, err
+	}
+
+	docBytes, err = os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromEditingFormat(config.Format, docBytes)
+}
+
+// redactJSONPlan replaces every sensitive value in the JSON plan with null. When there are
+// selectors only the values of the resource instances they select are redacted.
+func redactJSONPlan(doc map[string]any, selectors []addrs.Selector) error {
+	for _, v := range jsonPlanValues(doc) {
+		if !v.present() || (v.resource == "" && len(selectors) > 0) {
+			continue
+		}
+
+		if v.resource != "" {
+			selected, err := selectsInstance(selectors, v.resource)
+			if err != nil {
+				return fmt.Errorf("%s: %w", v.key(), err)
+			}
+			if !selected {
+				continue
+			}
+		}
+
+		v.parent[v.field] = redactJSONValue(v.parent[v.field], v.sensitive)
+	}
+
+	return nil
+}
+
+// redactJSONValue returns the value with everything that its sensitivity marks as sensitive
+// replaced with null.
+func redactJSONValue(v any, sensitive any) any {
+	switch s := sensitive.(type) {
+	case bool:
+		if s {
+			return nil
+		}
+	case map[string]any:
+		if m, ok := v.(map[string]any); ok {
+			for k := range m {
+				m[k] = redactJSONValue(m[k], s[k])
+			}
+		}
+	case []any:
+		if l, ok := v.([]any); ok {
+			for i := range l {
+				if i < len(s) {
+					l[i] = redactJSONValue(l[i], s[i])
+				}
+			}
+		}
+	}
+
+	return v
+}
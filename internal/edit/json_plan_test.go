@@ -0,0 +1,196 @@
+package edit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+)
+
+const testJSONPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.1",
+  "variables": {"token": {"value": "secret"}, "region": {"value": "us-east-1"}},
+  "planned_values": {
+    "outputs": {"password": {"sensitive": true, "value": "hunter2"}},
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.web", "values": {"ami": "ami-web", "user_data": "boot", "count": 10000000000000000001}, "sensitive_values": {"user_data": true}}
+      ]
+    }
+  },
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "change": {
+        "actions": ["update"],
+        "before": {"ami": "ami-old", "user_data": "old-boot", "tags": ["a", "b"]},
+        "after": {"ami": "ami-web", "user_data": "boot", "tags": ["a", "b"]},
+        "before_sensitive": {"user_data": true, "tags": [false, true]},
+        "after_sensitive": {"user_data": true, "tags": [false, true]}
+      }
+    },
+    {
+      "address": "aws_instance.web",
+      "deposed": "00000001",
+      "change": {"actions": ["delete"], "before": {"ami": "ami-older"}, "after": null}
+    }
+  ],
+  "output_changes": {
+    "password": {"actions": ["create"], "before": null, "after": "hunter2", "before_sensitive": false, "after_sensitive": true}
+  },
+  "configuration": {
+    "root_module": {"variables": {"token": {"sensitive": true}, "region": {}}}
+  }
+}
+`
+
+func requireJSONPlanFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte(testJSONPlan), 0o644))
+
+	return path
+}
+
+func requireReadJSONPlan(t *testing.T, path string) map[string]any {
+	t.Helper()
+
+	doc, err := readJSONPlan(path)
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestJSONPlanValues(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{}
+	for _, v := range jsonPlanValues(requireReadJSONPlan(t, requireJSONPlanFile(t))) {
+		keys = append(keys, v.key())
+	}
+
+	require.Equal(t, []string{
+		`variables["region"]`,
+		`variables["token"]`,
+		`resource_changes["aws_instance.web"].before`,
+		`resource_changes["aws_instance.web"].after`,
+		`resource_changes["aws_instance.web deposed 00000001"].before`,
+		`resource_changes["aws_instance.web deposed 00000001"].after`,
+		`output_changes["password"].before`,
+		`output_changes["password"].after`,
+		`planned_values["aws_instance.web"].values`,
+		`planned_values["output.password"].value`,
+	}, keys)
+}
+
+func TestEditJSONPlan(t *testing.T) {
+	t.Parallel()
+
+	for _, singleDocument := range []bool{false, true} {
+		planPath := requireJSONPlanFile(t)
+		dstPath := filepath.Join(t.TempDir(), "edited.json")
+
+		err := New(&Config{
+			PlanPath:       planPath,
+			DstPath:        dstPath,
+			TextEditorCmd:  sedEditor(t, `s/ami-web/ami-app/`, `s/1.9.1/1.9.2/`),
+			Format:         FormatYAML,
+			SingleDocument: singleDocument,
+		}).Edit()
+		require.NoError(t, err)
+
+		doc := requireReadJSONPlan(t, dstPath)
+		require.Equal(t, "1.9.2", doc["terraform_version"])
+
+		change := asMap(asMap(asSlice(doc["resource_changes"])[0])["change"])
+		require.Equal(t, "ami-old", asMap(change["before"])["ami"])
+		require.Equal(t, "ami-app", asMap(change["after"])["ami"])
+		require.Equal(t, true, asMap(change["after_sensitive"])["user_data"])
+
+		// Values keep their place in the plan, and large numbers aren't rounded.
+		deposed := asMap(asMap(asSlice(doc["resource_changes"])[1])["change"])
+		require.Equal(t, "ami-older", asMap(deposed["before"])["ami"])
+		require.Contains(t, deposed, "after")
+		require.Nil(t, deposed["after"])
+
+		resource := asMap(asSlice(asMap(asMap(doc["planned_values"])["root_module"])["resources"])[0])
+		require.Equal(t, "ami-app", asMap(resource["values"])["ami"])
+		require.Equal(t, json.Number("10000000000000000001"), asMap(resource["values"])["count"])
+	}
+}
+
+func TestEditJSONPlanRemovedResource(t *testing.T) {
+	t.Parallel()
+
+	err := New(&Config{
+		PlanPath:      requireJSONPlanFile(t),
+		DstPath:       filepath.Join(t.TempDir(), "edited.json"),
+		TextEditorCmd: sedEditor(t, `s/00000001/00000002/`),
+		Format:        FormatJSON,
+	}).Edit()
+	require.EqualError(t, err, `resource_changes["aws_instance.web deposed 00000001"].before: the edited plan no longer has the resource_changes the value belongs to`)
+}
+
+func TestRedactJSONPlan(t *testing.T) {
+	t.Parallel()
+
+	dstPath := filepath.Join(t.TempDir(), "redacted.json")
+	require.NoError(t, New(&Config{PlanPath: requireJSONPlanFile(t), DstPath: dstPath}).RedactSensitiveValues())
+
+	doc := requireReadJSONPlan(t, dstPath)
+	variables := asMap(doc["variables"])
+	require.Nil(t, asMap(variables["token"])["value"])
+	require.Equal(t, "us-east-1", asMap(variables["region"])["value"])
+
+	change := asMap(asMap(asSlice(doc["resource_changes"])[0])["change"])
+	require.Equal(t, map[string]any{"ami": "ami-old", "user_data": nil, "tags": []any{"a", nil}}, change["before"])
+	require.Equal(t, map[string]any{"ami": "ami-web", "user_data": nil, "tags": []any{"a", nil}}, change["after"])
+
+	output := asMap(asMap(doc["output_changes"])["password"])
+	require.Nil(t, output["after"])
+
+	planned := asMap(doc["planned_values"])
+	require.Nil(t, asMap(asMap(planned["outputs"])["password"])["value"])
+	resource := asMap(asSlice(asMap(planned["root_module"])["resources"])[0])
+	require.Equal(t, "ami-web", asMap(resource["values"])["ami"])
+	require.Nil(t, asMap(resource["values"])["user_data"])
+}
+
+func TestRedactJSONPlanSelectors(t *testing.T) {
+	t.Parallel()
+
+	for selector, redacted := range map[string]bool{
+		"aws_instance.web": true,
+		"aws_instance.*":   true,
+		"module.app":       false,
+	} {
+		t.Run(selector, func(t *testing.T) {
+			t.Parallel()
+
+			sel, err := addrs.ParseSelector(selector)
+			require.NoError(t, err)
+			dstPath := filepath.Join(t.TempDir(), "redacted.json")
+			require.NoError(t, New(&Config{
+				PlanPath:  requireJSONPlanFile(t),
+				DstPath:   dstPath,
+				Selectors: []addrs.Selector{sel},
+			}).RedactSensitiveValues())
+
+			doc := requireReadJSONPlan(t, dstPath)
+			require.Equal(t, "secret", asMap(asMap(doc["variables"])["token"])["value"])
+
+			change := asMap(asMap(asSlice(doc["resource_changes"])[0])["change"])
+			if redacted {
+				require.Nil(t, asMap(change["after"])["user_data"])
+			} else {
+				require.Equal(t, "boot", asMap(change["after"])["user_data"])
+			}
+		})
+	}
+}
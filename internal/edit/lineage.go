@@ -0,0 +1,115 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// lineageRe matches a UUID in the lowercase form that Terraform generates lineages in. Terraform
+// compares lineages as strings, so any other form would never match a lineage it generated.
+var lineageRe = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Lineage is the lineage and serial that can be set in state snapshots. Only non-nil fields are
+// changed.
+type Lineage struct {
+	Lineage *string
+	Serial  *uint64
+}
+
+// SetLineage validates and sets the lineage and serial of the tfstate and tfstate-prev snapshots
+// of the plan together, or of the state file if PlanPath is one.
+func (e *Editor) SetLineage(l *Lineage) error {
+	if l == nil || (l.Lineage == nil && l.Serial == nil) {
+		return errors.New("you must provide a lineage or serial to set")
+	}
+
+	kind, err := e.fileKind()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case jsonPlanFile:
+		return errors.New("JSON plans do not record the lineage or serial of their state")
+	case stateFile:
+		return e.updateState(func(s *state.State) error {
+			return setLineage([]*state.State{s}, l)
+		})
+	}
+
+	return e.updatePlanDir(func(dir string, _ *plan.Plan) error {
+		states, err := loadStateFiles(dir)
+		if err != nil {
+			return err
+		}
+
+		// The refreshed state in tfstate is the one Terraform compares with the current state
+		// when the plan is applied, so it comes first.
+		names := []string{}
+		snapshots := []*state.State{}
+		for _, name := range stateFileNames {
+			if s, ok := states[name]; ok {
+				names = append(names, name)
+				snapshots = append(snapshots, s)
+			}
+		}
+		if len(snapshots) == 0 {
+			return errors.New("the plan has no state snapshots")
+		}
+
+		if err = setLineage(snapshots, l); err != nil {
+			return err
+		}
+
+		for i, s := range snapshots {
+			b, err := s.Marshal()
+			if err != nil {
+				return err
+			}
+			if err = os.WriteFile(filepath.Join(dir, names[i]), b, 0o644); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// setLineage sets the same lineage and serial in every snapshot. Whichever of them isn't given is
+// taken from the first snapshot. Terraform writes every snapshot of a plan with the lineage and
+// serial of the state it planned against, refuses to apply the plan if the current state has
+// moved on from them, and refuses to replace a state with an older serial of the same lineage, so
+// a serial can only be lowered along with a new lineage.
+func setLineage(snapshots []*state.State, l *Lineage) error {
+	current := snapshots[0]
+	lineage, serial := current.Lineage, current.Serial
+
+	if v := l.Lineage; v != nil {
+		if !lineageRe.MatchString(*v) {
+			return fmt.Errorf("invalid lineage %q, must be a lowercase UUID, e.g. 0b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a", *v)
+		}
+		lineage = *v
+	}
+
+	if v := l.Serial; v != nil {
+		serial = *v
+	}
+
+	for _, s := range snapshots {
+		if s.Lineage == lineage && serial < s.Serial {
+			return fmt.Errorf("serial %d is older than serial %d of lineage %s, which Terraform refuses to replace, set a new lineage to lower the serial", serial, s.Serial, lineage)
+		}
+	}
+
+	for _, s := range snapshots {
+		s.Lineage = lineage
+		s.Serial = serial
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package edit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+const testLineage = "0b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a"
+
+func TestSetLineage(t *testing.T) {
+	t.Parallel()
+
+	prevStateFile := strings.Replace(testStateFile, `"serial": 7`, `"serial": 6`, 1)
+	for desc, test := range map[string]struct {
+		l       *Lineage
+		prev    string
+		lineage string
+		serial  uint64
+		err     string
+	}{
+		"lineage and serial": {
+			l:       &Lineage{Lineage: ptr("f47ac10b-58cc-4372-a567-0e02b2c3d479"), Serial: ptr[uint64](1)},
+			lineage: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			serial:  1,
+		},
+		"lineage": {
+			l:       &Lineage{Lineage: ptr("f47ac10b-58cc-4372-a567-0e02b2c3d479")},
+			prev:    prevStateFile,
+			lineage: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			serial:  7,
+		},
+		"newer serial": {
+			l:       &Lineage{Serial: ptr[uint64](9)},
+			lineage: testLineage,
+			serial:  9,
+		},
+		"same serial": {
+			l:       &Lineage{Serial: ptr[uint64](7)},
+			prev:    prevStateFile,
+			lineage: testLineage,
+			serial:  7,
+		},
+		"older serial": {
+			l:   &Lineage{Serial: ptr[uint64](6)},
+			err: "serial 6 is older than serial 7 of lineage " + testLineage + ", which Terraform refuses to replace, set a new lineage to lower the serial",
+		},
+		"older serial than tfstate-prev": {
+			l:    &Lineage{Lineage: ptr(testLineage)},
+			prev: strings.Replace(testStateFile, `"serial": 7`, `"serial": 8`, 1),
+			err:  "serial 7 is older than serial 8 of lineage " + testLineage,
+		},
+		"uppercase lineage": {
+			l:   &Lineage{Lineage: ptr(strings.ToUpper(testLineage))},
+			err: "invalid lineage",
+		},
+		"invalid lineage": {
+			l:   &Lineage{Lineage: ptr("fixture")},
+			err: "invalid lineage",
+		},
+		"nothing to set": {
+			l:   &Lineage{},
+			err: "you must provide a lineage or serial to set",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			prev := test.prev
+			if prev == "" {
+				prev = testStateFile
+			}
+			planPath := requirePlanFile(t, &plan.Plan{Version: 3, TerraformVersion: "1.9.1"}, map[string]string{
+				"tfstate":      testStateFile,
+				"tfstate-prev": prev,
+			})
+			dstPath := filepath.Join(t.TempDir(), "edited.plan")
+			err := New(&Config{PlanPath: planPath, DstPath: dstPath}).SetLineage(test.l)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			files := requireZipFiles(t, dstPath)
+			for _, name := range stateFileNames {
+				s, err := state.Parse([]byte(files[name]))
+				require.NoError(t, err)
+				require.Equal(t, test.lineage, s.Lineage, name)
+				require.Equal(t, test.serial, s.Serial, name)
+			}
+		})
+	}
+}
+
+func TestSetLineageState(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{PlanPath: requireStateFile(t), DstPath: filepath.Join(t.TempDir(), "edited.tfstate")}
+	require.NoError(t, New(config).SetLineage(&Lineage{Serial: ptr[uint64](8)}))
+
+	s := requireReadState(t, config.DstPath)
+	require.Equal(t, testLineage, s.Lineage)
+	require.EqualValues(t, 8, s.Serial)
+}
+
+func TestSetLineageWithoutStates(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, &plan.Plan{Version: 3, TerraformVersion: "1.9.1"}, nil)
+	err := New(&Config{PlanPath: planPath, DstPath: filepath.Join(t.TempDir(), "edited.plan")}).SetLineage(&Lineage{Serial: ptr[uint64](1)})
+	require.EqualError(t, err, "the plan has no state snapshots")
+}
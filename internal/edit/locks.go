@@ -0,0 +1,387 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// lockFileName is the name of the dependency lock file in a plan.
+const lockFileName = ".terraform.lock.hcl"
+
+// lockFileHeader is the comment that Terraform writes at the top of every dependency lock file.
+const lockFileHeader = `# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+`
+
+// h1HashRe and zhHashRe match the two hash schemes of the dependency lock file. h1 is the
+// base64 encoded SHA-256 of a provider package's contents, and zh is the hex encoded SHA-256 of
+// a provider package's zip archive.
+var (
+	h1HashRe = regexp.MustCompile(`^h1:[A-Za-z0-9+/]{43}=$`)
+	zhHashRe = regexp.MustCompile(`^zh:[0-9a-f]{64}$`)
+)
+
+// providerLock is a provider block of the dependency lock file.
+type providerLock struct {
+	provider    addrs.Provider
+	version     string
+	constraints string
+	hashes      []string
+}
+
+// parseLockFile parses the provider blocks of a dependency lock file, keyed by provider source
+// address.
+func parseLockFile(bytes []byte) (map[string]*providerLock, error) {
+	file, diags := hclsyntax.ParseConfig(bytes, lockFileName, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse %s: %w", lockFileName, diags)
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse %s", lockFileName)
+	}
+
+	locks := map[string]*providerLock{}
+	for _, block := range body.Blocks {
+		if block.Type != "provider" {
+			return nil, fmt.Errorf("%s line %d: unexpected %s block", lockFileName, block.TypeRange.Start.Line, block.Type)
+		}
+		if len(block.Labels) != 1 {
+			return nil, fmt.Errorf("%s line %d: a provider block must have the provider source address as its only label", lockFileName, block.TypeRange.Start.Line)
+		}
+
+		p, err := addrs.ParseProvider(block.Labels[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", lockFileName, block.TypeRange.Start.Line, err)
+		}
+		if _, ok := locks[p.String()]; ok {
+			return nil, fmt.Errorf("%s line %d: provider %s is locked more than once", lockFileName, block.TypeRange.Start.Line, p)
+		}
+
+		lock := &providerLock{provider: p}
+		for _, name := range mapkeys.Sorted(block.Body.Attributes) {
+			attr := block.Body.Attributes[name]
+			errorf := func(format string, args ...any) error {
+				return fmt.Errorf("%s line %d: %s: %s", lockFileName, attr.SrcRange.Start.Line, p, fmt.Sprintf(format, args...))
+			}
+
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return nil, errorf("%s", diags.Error())
+			}
+
+			switch name {
+			case "version", "constraints":
+				if val.IsNull() || !val.IsKnown() || val.Type() != cty.String {
+					return nil, errorf("%s must be a string", name)
+				}
+				if name == "version" {
+					lock.version = val.AsString()
+				} else {
+					lock.constraints = val.AsString()
+				}
+			case "hashes":
+				if val.IsNull() || !val.IsKnown() || !(val.Type().IsListType() || val.Type().IsTupleType()) {
+					return nil, errorf("hashes must be a list of strings")
+				}
+				for _, h := range val.AsValueSlice() {
+					if h.IsNull() || !h.IsKnown() || h.Type() != cty.String {
+						return nil, errorf("hashes must be a list of strings")
+					}
+					lock.hashes = append(lock.hashes, h.AsString())
+				}
+			default:
+				return nil, errorf("unexpected attribute %q", name)
+			}
+		}
+		if lock.version == "" {
+			return nil, fmt.Errorf("%s line %d: %s: the version is required", lockFileName, block.TypeRange.Start.Line, p)
+		}
+
+		locks[p.String()] = lock
+	}
+
+	return locks, nil
+}
+
+// marshalLockFile writes the dependency lock file the way Terraform does, with the providers
+// and their hashes in order.
+func marshalLockFile(locks map[string]*providerLock) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{Type: hclsyntax.TokenComment, Bytes: []byte(lockFileHeader)},
+	})
+
+	for _, name := range mapkeys.Sorted(locks) {
+		lock := locks[name]
+		body.AppendNewline()
+		block := body.AppendNewBlock("provider", []string{name}).Body()
+		block.SetAttributeValue("version", cty.StringVal(lock.version))
+		if lock.constraints != "" {
+			block.SetAttributeValue("constraints", cty.StringVal(lock.constraints))
+		}
+		if len(lock.hashes) > 0 {
+			block.SetAttributeRaw("hashes", hashesTokens(lock.hashes))
+		}
+	}
+
+	return hclwrite.Format(f.Bytes())
+}
+
+// hashesTokens returns the tokens of a list of hashes with one hash per line, as Terraform writes
+// them.
+func hashesTokens(hashes []string) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")},
+		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+	}
+	for _, h := range hashes {
+		tokens = append(tokens, hclwrite.TokensForValue(cty.StringVal(h))...)
+		tokens = append(tokens,
+			&hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")},
+			&hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+		)
+	}
+
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+}
+
+// planProviders returns the providers of the plan's resource instance changes, with the first
+// address that uses each of them. Terraform's built-in providers are never locked, so they're
+// left out.
+func planProviders(p *plan.Plan) (map[string]string, error) {
+	changes := append([]*plan.ResourceInstanceChange{}, p.GetResourceChanges()...)
+	changes = append(changes, p.GetResourceDrift()...)
+	for _, d := range p.GetDeferredChanges() {
+		changes = append(changes, d.GetChange())
+	}
+
+	providers := map[string]string{}
+	for _, c := range changes {
+		if c.GetProvider() == "" {
+			continue
+		}
+
+		pc, err := addrs.ParseProviderConfig(c.GetProvider())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", resourceInstanceChangeKey(c), err)
+		}
+		if pc.Provider.Hostname == "terraform.io" && pc.Provider.Namespace == "builtin" {
+			continue
+		}
+		if _, ok := providers[pc.Provider.String()]; !ok {
+			providers[pc.Provider.String()] = c.GetAddr()
+		}
+	}
+
+	return providers, nil
+}
+
+// checkPlanLocks returns an error for each provider that the plan uses without a lock in the
+// dependency lock file of the plan unpacked in dir, which Terraform would refuse to apply, and
+// warns about locks for providers the plan doesn't use. Plans without a lock file are not
+// checked.
+func checkPlanLocks(dir string, p *plan.Plan) error {
+	bytes, err := os.ReadFile(filepath.Join(dir, lockFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	locks, err := parseLockFile(bytes)
+	if err != nil {
+		return err
+	}
+
+	providers, err := planProviders(p)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, name := range mapkeys.Sorted(providers) {
+		if _, ok := locks[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: provider %s is not locked in %s", providers[name], name, lockFileName))
+		}
+	}
+
+	for _, name := range mapkeys.Sorted(locks) {
+		if _, ok := providers[name]; !ok {
+			fmt.Printf("warning: %s: provider %s is locked but the plan doesn't use it\n", lockFileName, name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReportLocks writes a table of the providers that are locked or used by the plan.
+func (e *Editor) ReportLocks(w io.Writer) error {
+	p, err := e.readPlan()
+	if err != nil {
+		return err
+	}
+
+	bytes, err := e.readPlanFile(lockFileName)
+	if err != nil {
+		return err
+	}
+
+	locks, err := parseLockFile(bytes)
+	if err != nil {
+		return err
+	}
+
+	providers, err := planProviders(p)
+	if err != nil {
+		return err
+	}
+
+	names := mapkeys.Sorted(locks)
+	for name := range providers {
+		if _, ok := locks[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tVERSION\tCONSTRAINTS\tHASHES\tUSED")
+	for _, name := range names {
+		_, used := providers[name]
+		lock, ok := locks[name]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t(not locked)\t\t\t%t\n", name, used)
+			continue
+		}
+
+		h1, zh := 0, 0
+		for _, h := range lock.hashes {
+			switch {
+			case strings.HasPrefix(h, "h1:"):
+				h1++
+			case strings.HasPrefix(h, "zh:"):
+				zh++
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d h1, %d zh\t%t\n", name, lock.version, lock.constraints, h1, zh, used)
+	}
+
+	return tw.Flush()
+}
+
+// AddLockHashes adds the h1: or zh: hashes to the provider's lock. Hashes that the lock already
+// has are left as they are.
+func (e *Editor) AddLockHashes(provider string, hashes []string) error {
+	for _, h := range hashes {
+		if !h1HashRe.MatchString(h) && !zhHashRe.MatchString(h) {
+			return fmt.Errorf("invalid hash %q, must be an h1: hash of 44 base64 characters or a zh: hash of 64 hex characters", h)
+		}
+	}
+
+	return e.updateProviderLock(provider, func(locks map[string]*providerLock, lock *providerLock) error {
+		seen := map[string]bool{}
+		all := []string{}
+		for _, h := range append(lock.hashes, hashes...) {
+			if !seen[h] {
+				seen[h] = true
+				all = append(all, h)
+			}
+		}
+		sort.Strings(all)
+		lock.hashes = all
+
+		return nil
+	})
+}
+
+// RemoveLockHashes removes the hashes from the provider's lock. Each hash must be in the lock.
+func (e *Editor) RemoveLockHashes(provider string, hashes []string) error {
+	return e.updateProviderLock(provider, func(locks map[string]*providerLock, lock *providerLock) error {
+		remove := map[string]bool{}
+		for _, h := range hashes {
+			remove[h] = true
+		}
+
+		kept := []string{}
+		for _, h := range lock.hashes {
+			if remove[h] {
+				delete(remove, h)
+				continue
+			}
+			kept = append(kept, h)
+		}
+		if len(remove) > 0 {
+			return fmt.Errorf("provider %s is not locked with %s", lock.provider, strings.Join(mapkeys.Sorted(remove), ", "))
+		}
+		lock.hashes = kept
+
+		return nil
+	})
+}
+
+// RemoveLock drops the provider's lock. The plan must not use the provider.
+func (e *Editor) RemoveLock(provider string) error {
+	return e.updateProviderLock(provider, func(locks map[string]*providerLock, lock *providerLock) error {
+		delete(locks, lock.provider.String())
+		return nil
+	})
+}
+
+// updateProviderLock applies the update to the provider's lock in the plan's dependency lock file
+// and checks the plan's providers against the updated locks before the plan is written.
+func (e *Editor) updateProviderLock(provider string, update func(locks map[string]*providerLock, lock *providerLock) error) error {
+	pa, err := addrs.ParseProvider(provider)
+	if err != nil {
+		return err
+	}
+
+	return e.updatePlanDir(func(dir string, p *plan.Plan) error {
+		path := filepath.Join(dir, lockFileName)
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s from Terraform plan: %w", lockFileName, err)
+		}
+
+		locks, err := parseLockFile(bytes)
+		if err != nil {
+			return err
+		}
+
+		lock, ok := locks[pa.String()]
+		if !ok {
+			return fmt.Errorf("provider %s is not locked in %s", pa, lockFileName)
+		}
+
+		if err = update(locks, lock); err != nil {
+			return err
+		}
+
+		if err = os.WriteFile(path, marshalLockFile(locks), 0o644); err != nil {
+			return err
+		}
+
+		return checkPlanLocks(dir, p)
+	})
+}
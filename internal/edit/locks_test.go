@@ -0,0 +1,201 @@
+package edit
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+const (
+	testH1Hash  = "h1:9mkMtIspf1P1ACq1yaKZ6ZJ4vD9XrNmNR4DjaLvpMM8="
+	testH1Hash2 = "h1:AAAAtIspf1P1ACq1yaKZ6ZJ4vD9XrNmNR4DjaLvpMM8="
+	testZHHash  = "zh:0fc45e5bf0fc0a1c8b0e1d9e1b39c2e1d3e2d3d4d7b1a8e1d6e1f5a7c3e2b1a0"
+)
+
+const testLockFile = `# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "` + testH1Hash + `",
+    "` + testZHHash + `",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+  hashes = [
+    "` + testH1Hash + `",
+  ]
+}
+`
+
+func testLocksPlan() *plan.Plan {
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_instance.web",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"].west`,
+				Change:   &plan.Change{Action: plan.Action_NOOP},
+			},
+			{
+				Addr:     "terraform_data.id",
+				Provider: `provider["terraform.io/builtin/terraform"]`,
+				Change:   &plan.Change{Action: plan.Action_NOOP},
+			},
+		},
+	}
+}
+
+func TestParseLockFile(t *testing.T) {
+	t.Parallel()
+
+	locks, err := parseLockFile([]byte(testLockFile))
+	require.NoError(t, err)
+	require.Equal(t, []string{"registry.terraform.io/hashicorp/aws", "registry.terraform.io/hashicorp/random"}, mapkeys.Sorted(locks))
+	aws := locks["registry.terraform.io/hashicorp/aws"]
+	require.Equal(t, "5.31.0", aws.version)
+	require.Equal(t, "~> 5.0", aws.constraints)
+	require.Equal(t, []string{testH1Hash, testZHHash}, aws.hashes)
+
+	// Lock files are written back exactly as Terraform writes them.
+	require.Equal(t, testLockFile, string(marshalLockFile(locks)))
+
+	for desc, test := range map[string]struct {
+		file string
+		err  string
+	}{
+		"syntax":    {file: `provider "aws" {`, err: "unable to parse .terraform.lock.hcl"},
+		"block":     {file: "module \"a\" {\n}\n", err: ".terraform.lock.hcl line 1: unexpected module block"},
+		"version":   {file: "provider \"hashicorp/aws\" {\n}\n", err: "registry.terraform.io/hashicorp/aws: the version is required"},
+		"hashes":    {file: "provider \"hashicorp/aws\" {\n  version = \"1.0.0\"\n  hashes = \"h1:\"\n}\n", err: "hashes must be a list of strings"},
+		"attribute": {file: "provider \"hashicorp/aws\" {\n  version = \"1.0.0\"\n  source = \"x\"\n}\n", err: `unexpected attribute "source"`},
+	} {
+		_, err := parseLockFile([]byte(test.file))
+		require.ErrorContains(t, err, test.err, desc)
+	}
+}
+
+func TestReportLocks(t *testing.T) {
+	t.Parallel()
+
+	p := testLocksPlan()
+	p.ResourceChanges = append(p.ResourceChanges, &plan.ResourceInstanceChange{
+		Addr:     "google_compute_instance.web",
+		Provider: `provider["registry.terraform.io/hashicorp/google"]`,
+		Change:   &plan.Change{Action: plan.Action_NOOP},
+	})
+	planPath := requirePlanFile(t, p, map[string]string{lockFileName: testLockFile})
+
+	out := bytes.Buffer{}
+	require.NoError(t, New(&Config{PlanPath: planPath}).ReportLocks(&out))
+	require.Equal(t, strings.Join([]string{
+		"PROVIDER                                VERSION       CONSTRAINTS  HASHES      USED",
+		"registry.terraform.io/hashicorp/aws     5.31.0        ~> 5.0       1 h1, 1 zh  true",
+		"registry.terraform.io/hashicorp/google  (not locked)                           true",
+		"registry.terraform.io/hashicorp/random  3.6.0                      1 h1, 0 zh  false",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestEditLocks(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		update func(e *Editor) error
+		hashes []string
+		locked []string
+		err    string
+	}{
+		"add hashes": {
+			update: func(e *Editor) error {
+				return e.AddLockHashes("hashicorp/aws", []string{testZHHash, testH1Hash2})
+			},
+			hashes: []string{testH1Hash, testH1Hash2, testZHHash},
+			locked: []string{"registry.terraform.io/hashicorp/aws", "registry.terraform.io/hashicorp/random"},
+		},
+		"add invalid hash": {
+			update: func(e *Editor) error {
+				return e.AddLockHashes("hashicorp/aws", []string{"h1:abc"})
+			},
+			err: `invalid hash "h1:abc"`,
+		},
+		"remove hashes": {
+			update: func(e *Editor) error {
+				return e.RemoveLockHashes("registry.terraform.io/hashicorp/aws", []string{testZHHash})
+			},
+			hashes: []string{testH1Hash},
+			locked: []string{"registry.terraform.io/hashicorp/aws", "registry.terraform.io/hashicorp/random"},
+		},
+		"remove missing hash": {
+			update: func(e *Editor) error {
+				return e.RemoveLockHashes("hashicorp/aws", []string{testH1Hash2})
+			},
+			err: "provider registry.terraform.io/hashicorp/aws is not locked with " + testH1Hash2,
+		},
+		"remove unused provider": {
+			update: func(e *Editor) error {
+				return e.RemoveLock("random")
+			},
+			hashes: []string{testH1Hash, testZHHash},
+			locked: []string{"registry.terraform.io/hashicorp/aws"},
+		},
+		"remove used provider": {
+			update: func(e *Editor) error {
+				return e.RemoveLock("hashicorp/aws")
+			},
+			err: "aws_instance.web: provider registry.terraform.io/hashicorp/aws is not locked in .terraform.lock.hcl",
+		},
+		"unlocked provider": {
+			update: func(e *Editor) error {
+				return e.RemoveLock("hashicorp/google")
+			},
+			err: "provider registry.terraform.io/hashicorp/google is not locked in .terraform.lock.hcl",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			planPath := requirePlanFile(t, testLocksPlan(), map[string]string{lockFileName: testLockFile})
+			dstPath := filepath.Join(t.TempDir(), "edited.plan")
+			err := test.update(New(&Config{PlanPath: planPath, DstPath: dstPath}))
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				require.NoFileExists(t, dstPath)
+				return
+			}
+			require.NoError(t, err)
+
+			locks, err := parseLockFile([]byte(requireZipFiles(t, dstPath)[lockFileName]))
+			require.NoError(t, err)
+			require.Equal(t, test.locked, mapkeys.Sorted(locks))
+			require.Equal(t, test.hashes, locks["registry.terraform.io/hashicorp/aws"].hashes)
+		})
+	}
+}
+
+func TestEditChecksLocks(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testLocksPlan(), map[string]string{lockFileName: testLockFile})
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	err := New(&Config{
+		PlanPath: planPath,
+		DstPath:  dstPath,
+		// Only the lock file has a provider block to rename.
+		TextEditorCmd: sedEditor(t, `s/\/aws"\(.\){/\/awsx"\1{/`),
+		Format:        FormatJSON,
+	}).Edit()
+	require.EqualError(t, err, "aws_instance.web: provider registry.terraform.io/hashicorp/aws is not locked in .terraform.lock.hcl")
+	require.NoFileExists(t, dstPath)
+}
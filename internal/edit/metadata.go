@@ -0,0 +1,100 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// semverRe matches a semantic version as defined by https://semver.org without a "v" prefix.
+var semverRe = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// Metadata is the plan metadata that can be set. Only non-nil fields are changed.
+type Metadata struct {
+	TerraformVersion *string
+	Timestamp        *string
+	UIMode           *string
+	Applyable        *bool
+	Complete         *bool
+	Errored          *bool
+}
+
+// SetMetadata validates and sets the plan metadata.
+func (e *Editor) SetMetadata(m *Metadata) error {
+	if m == nil {
+		return errors.New("you must provide metadata to set")
+	}
+
+	return e.updatePlan(func(p *plan.Plan) error {
+		return setMetadata(p, m)
+	})
+}
+
+func setMetadata(p *plan.Plan, m *Metadata) error {
+	if v := m.TerraformVersion; v != nil {
+		if !semverRe.MatchString(*v) {
+			return fmt.Errorf("invalid terraform version %q, must be a semantic version, e.g. 1.9.1", *v)
+		}
+		p.TerraformVersion = *v
+	}
+
+	if v := m.Timestamp; v != nil {
+		if _, err := time.Parse(time.RFC3339, *v); err != nil {
+			return fmt.Errorf("invalid timestamp %q, must be an RFC3339 timestamp, e.g. 2024-07-08T17:19:28Z: %w", *v, err)
+		}
+		p.Timestamp = *v
+	}
+
+	if v := m.UIMode; v != nil {
+		mode, ok := plan.Mode_value[strings.ToUpper(*v)]
+		if !ok {
+			return fmt.Errorf("invalid mode %q, must be one of NORMAL, DESTROY or REFRESH_ONLY", *v)
+		}
+		if err := validateModeActions(plan.Mode(mode), p); err != nil {
+			return err
+		}
+		p.UiMode = plan.Mode(mode)
+	}
+
+	if v := m.Applyable; v != nil {
+		p.Applyable = *v
+	}
+
+	if v := m.Complete; v != nil {
+		p.Complete = *v
+	}
+
+	if v := m.Errored; v != nil {
+		p.Errored = *v
+	}
+
+	if p.GetErrored() && p.GetApplyable() {
+		return errors.New("an errored plan cannot be applyable")
+	}
+
+	return nil
+}
+
+// validateModeActions returns an error if the planned actions are not possible in the mode. A
+// destroy plan only deletes objects and a refresh only plan does not change any objects.
+func validateModeActions(mode plan.Mode, p *plan.Plan) error {
+	errs := []error{}
+
+	for _, c := range p.GetResourceChanges() {
+		action := c.GetChange().GetAction()
+		switch {
+		case mode == plan.Mode_DESTROY && action != plan.Action_DELETE:
+			errs = append(errs, fmt.Errorf("%s: a %s plan can only contain deletes, found %s", resourceInstanceChangeKey(c), mode, action))
+		case mode == plan.Mode_REFRESH_ONLY && action != plan.Action_NOOP:
+			errs = append(errs, fmt.Errorf("%s: a %s plan can only contain no-ops, found %s", resourceInstanceChangeKey(c), mode, action))
+		}
+	}
+
+	return errors.Join(errs...)
+}
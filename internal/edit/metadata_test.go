@@ -0,0 +1,102 @@
+package edit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSetMetadata(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		Timestamp:        "2024-07-08T17:19:28Z",
+		Applyable:        true,
+		Complete:         true,
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{Addr: "aws_instance.web", Change: &plan.Change{Action: plan.Action_DELETE}},
+		},
+	}, nil)
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).SetMetadata(&Metadata{
+		TerraformVersion: ptr("1.10.0-alpha20240606"),
+		Timestamp:        ptr("2000-01-01T00:00:00Z"),
+		UIMode:           ptr("destroy"),
+		Complete:         ptr(false),
+	}))
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	require.Equal(t, "1.10.0-alpha20240606", p.GetTerraformVersion())
+	require.Equal(t, "2000-01-01T00:00:00Z", p.GetTimestamp())
+	require.Equal(t, plan.Mode_DESTROY, p.GetUiMode())
+	require.True(t, p.GetApplyable())
+	require.False(t, p.GetComplete())
+	require.False(t, p.GetErrored())
+}
+
+func TestSetMetadataValidation(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		m   *Metadata
+		err string
+	}{
+		"version prefix": {
+			m:   &Metadata{TerraformVersion: ptr("v1.9.1")},
+			err: "invalid terraform version",
+		},
+		"version incomplete": {
+			m:   &Metadata{TerraformVersion: ptr("1.9")},
+			err: "invalid terraform version",
+		},
+		"timestamp": {
+			m:   &Metadata{Timestamp: ptr("2024-07-08 17:19:28")},
+			err: "invalid timestamp",
+		},
+		"mode": {
+			m:   &Metadata{UIMode: ptr("apply")},
+			err: "invalid mode",
+		},
+		"destroy with creates": {
+			m:   &Metadata{UIMode: ptr("DESTROY")},
+			err: "aws_instance.web: a DESTROY plan can only contain deletes, found CREATE",
+		},
+		"refresh only with creates": {
+			m:   &Metadata{UIMode: ptr("REFRESH_ONLY")},
+			err: "aws_instance.web: a REFRESH_ONLY plan can only contain no-ops, found CREATE",
+		},
+		"errored and applyable": {
+			m:   &Metadata{Errored: ptr(true)},
+			err: "an errored plan cannot be applyable",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &plan.Plan{
+				Applyable: true,
+				ResourceChanges: []*plan.ResourceInstanceChange{
+					{Addr: "aws_instance.web", Change: &plan.Change{Action: plan.Action_CREATE}},
+					{Addr: "aws_instance.db", Change: &plan.Change{Action: plan.Action_NOOP}},
+				},
+			}
+			require.ErrorContains(t, setMetadata(p, test.m), test.err)
+		})
+	}
+
+	p := &plan.Plan{Applyable: true}
+	require.NoError(t, setMetadata(p, &Metadata{Errored: ptr(true), Applyable: ptr(false), UIMode: ptr("REFRESH_ONLY")}))
+	require.True(t, p.GetErrored())
+	require.Equal(t, plan.Mode_REFRESH_ONLY, p.GetUiMode())
+}
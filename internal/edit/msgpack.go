@@ -0,0 +1,136 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// impliedType returns the cty type implied by the structure of msgpack encoded bytes. It follows the
+// same rules as ctymsgpack.ImpliedType except that it also understands values that were encoded
+// with cty.DynamicPseudoType. Those are encoded as a two element array of the JSON type and the
+// value, which ctymsgpack.ImpliedType is unable to handle because the type is binary data. When we
+// find one we imply the DynamicPseudoType so that ctymsgpack.Unmarshal will decode the value with
+// its real type and ctymsgpack.Marshal will preserve the dynamic type wrapper.
+func impliedType(buf []byte) (cty.Type, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(buf))
+
+	ty, err := impliedTypeDec(dec)
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	if err = dec.Skip(); !errors.Is(err, io.EOF) {
+		return cty.NilType, errors.New("extra bytes after msgpack value")
+	}
+
+	return ty, nil
+}
+
+func impliedTypeDec(dec *msgpack.Decoder) (cty.Type, error) {
+	code, err := dec.PeekCode()
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	switch {
+	case code == msgpcode.Nil || msgpcode.IsExt(code):
+		return cty.DynamicPseudoType, dec.Skip()
+	case code == msgpcode.True || code == msgpcode.False:
+		_, err := dec.DecodeBool()
+		return cty.Bool, err
+	case msgpcode.IsFixedNum(code),
+		code == msgpcode.Int8, code == msgpcode.Int16, code == msgpcode.Int32, code == msgpcode.Int64,
+		code == msgpcode.Uint8, code == msgpcode.Uint16, code == msgpcode.Uint32, code == msgpcode.Uint64,
+		code == msgpcode.Float, code == msgpcode.Double:
+		return cty.Number, dec.Skip()
+	case msgpcode.IsString(code):
+		_, err := dec.DecodeString()
+		return cty.String, err
+	case msgpcode.IsFixedMap(code), code == msgpcode.Map16, code == msgpcode.Map32:
+		return impliedObjectType(dec)
+	case msgpcode.IsFixedArray(code), code == msgpcode.Array16, code == msgpcode.Array32:
+		return impliedTupleType(dec)
+	default:
+		return cty.NilType, fmt.Errorf("unsupported msgpack code %#v", code)
+	}
+}
+
+func impliedObjectType(dec *msgpack.Decoder) (cty.Type, error) {
+	l, err := dec.DecodeMapLen()
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	atys := map[string]cty.Type{}
+	for range l {
+		k, err := dec.DecodeString()
+		if err != nil {
+			return cty.NilType, err
+		}
+
+		atys[k], err = impliedTypeDec(dec)
+		if err != nil {
+			return cty.NilType, err
+		}
+	}
+
+	if len(atys) == 0 {
+		return cty.EmptyObject, nil
+	}
+
+	return cty.Object(atys), nil
+}
+
+func impliedTupleType(dec *msgpack.Decoder) (cty.Type, error) {
+	l, err := dec.DecodeArrayLen()
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	if l == 0 {
+		return cty.EmptyTuple, nil
+	}
+
+	// A dynamic value is always a two element array where the first element is the JSON encoding
+	// of the type as binary data. Nothing else in cty's msgpack encoding uses binary data.
+	if l == 2 {
+		code, err := dec.PeekCode()
+		if err != nil {
+			return cty.NilType, err
+		}
+
+		if isBin(code) {
+			typeJSON, err := dec.DecodeBytes()
+			if err != nil {
+				return cty.NilType, err
+			}
+
+			var ty cty.Type
+			if err = ty.UnmarshalJSON(typeJSON); err != nil {
+				return cty.NilType, fmt.Errorf("invalid dynamic value type: %w", err)
+			}
+
+			return cty.DynamicPseudoType, dec.Skip()
+		}
+	}
+
+	etys := make([]cty.Type, l)
+	for i := range l {
+		etys[i], err = impliedTypeDec(dec)
+		if err != nil {
+			return cty.NilType, err
+		}
+	}
+
+	return cty.Tuple(etys), nil
+}
+
+func isBin(code byte) bool {
+	return code == msgpcode.Bin8 || code == msgpcode.Bin16 || code == msgpcode.Bin32
+}
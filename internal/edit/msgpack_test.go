@@ -0,0 +1,104 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+)
+
+func TestImpliedTypeDynamicRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	manifest := cty.ObjectVal(map[string]cty.Value{
+		"kind": cty.StringVal("ConfigMap"),
+		"data": cty.MapVal(map[string]cty.Value{"token": cty.StringVal("secret")}),
+	})
+
+	for desc, test := range map[string]struct {
+		val      cty.Value
+		ty       cty.Type
+		edited   string
+		expected cty.Value
+	}{
+		"variable": {
+			val:      cty.StringVal("secret"),
+			ty:       cty.DynamicPseudoType,
+			edited:   `{"value":"redacted","type":"string"}`,
+			expected: cty.StringVal("redacted"),
+		},
+		"dynamic attribute": {
+			val: cty.ObjectVal(map[string]cty.Value{
+				"id":       cty.StringVal("default/cm"),
+				"manifest": manifest,
+				"object":   cty.DynamicVal,
+			}),
+			ty: cty.Object(map[string]cty.Type{
+				"id":       cty.String,
+				"manifest": cty.DynamicPseudoType,
+				"object":   cty.DynamicPseudoType,
+			}),
+			edited: `{
+				"id": "default/cm",
+				"manifest": {
+					"value": {"data": {"token": "redacted"}, "kind": "ConfigMap"},
+					"type": ["object", {"data": ["map", "string"], "kind": "string"}]
+				},
+				"object": {"$unknown": true}
+			}`,
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("default/cm"),
+				"manifest": cty.ObjectVal(map[string]cty.Value{
+					"kind": cty.StringVal("ConfigMap"),
+					"data": cty.MapVal(map[string]cty.Value{"token": cty.StringVal("redacted")}),
+				}),
+				"object": cty.DynamicVal,
+			}),
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			in, err := ctymsgpack.Marshal(test.val, test.ty)
+			require.NoError(t, err)
+
+			_, err = ctymsgpack.ImpliedType(in)
+			require.Error(t, err, "cty should be unable to imply dynamic types")
+
+			typ, err := impliedType(in)
+			require.NoError(t, err)
+			require.True(t, test.ty.Equals(typ), typ.GoString())
+
+			val, err := ctymsgpack.Unmarshal(in, typ)
+			require.NoError(t, err)
+			require.True(t, test.val.RawEquals(val), val.GoString())
+
+			b, err := marshalValueJSON(val, typ)
+			require.NoError(t, err)
+			got, err := unmarshalValueJSON(b, typ)
+			require.NoError(t, err)
+			out, err := ctymsgpack.Marshal(got, typ)
+			require.NoError(t, err)
+			require.Equal(t, in, out)
+
+			got, err = unmarshalValueJSON([]byte(test.edited), typ)
+			require.NoError(t, err)
+			out, err = ctymsgpack.Marshal(got, typ)
+			require.NoError(t, err)
+			expected, err := ctymsgpack.Marshal(test.expected, test.ty)
+			require.NoError(t, err)
+			require.Equal(t, expected, out)
+		})
+	}
+}
+
+func TestImpliedTypeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := impliedType([]byte{0xc1})
+	require.Error(t, err)
+
+	_, err = impliedType([]byte{0xc0, 0xc0})
+	require.ErrorContains(t, err, "extra bytes")
+}
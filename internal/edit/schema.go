@@ -0,0 +1,66 @@
+package edit
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+)
+
+// dynamicValueSchemaType returns the type of the value according to the schemas. It returns
+// cty.NilType if there are no schemas or they don't describe the value, in which case the type
+// has to be inferred from the value.
+func dynamicValueSchemaType(schemas *schema.Schemas, d dynamicValue) cty.Type {
+	block, err := dynamicValueSchema(schemas, d)
+	if err != nil {
+		fmt.Printf("schema: %s: %s, inferring its type\n", d.key(), err)
+		return cty.NilType
+	}
+
+	if block == nil {
+		return cty.NilType
+	}
+
+	return block.ImpliedType()
+}
+
+// dynamicValueSchema returns the schema of the value. It returns nil if there are no schemas or
+// the value is not a resource or backend value, and an error if the schemas don't describe it.
+func dynamicValueSchema(schemas *schema.Schemas, d dynamicValue) (*schema.Block, error) {
+	switch {
+	case schemas == nil:
+		return nil, nil
+	case d.resource != nil:
+		return resourceSchema(schemas, d.resource.GetAddr(), d.resource.GetProvider())
+	case d.backend != "":
+		return schemas.Backend(d.backend)
+	default:
+		return nil, nil
+	}
+}
+
+// resourceSchema returns the schema of the resource at the address, which is managed by the
+// provider configuration.
+func resourceSchema(schemas *schema.Schemas, addr string, providerConfig string) (*schema.Block, error) {
+	a, err := addrs.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if a.Resource == nil {
+		return nil, fmt.Errorf("%s is not a resource address", addr)
+	}
+
+	pc, err := addrs.ParseProviderConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := schemas.Resource(pc.Provider, a.Resource.Mode, a.Resource.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Block, nil
+}
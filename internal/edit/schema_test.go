@@ -0,0 +1,165 @@
+package edit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+)
+
+const testSchemasJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_instance": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true},
+              "ami": {"type": "string", "required": true},
+              "security_groups": {"type": ["set", "string"], "optional": true},
+              "tags": {"type": ["map", "string"], "optional": true}
+            },
+            "block_types": {
+              "root_block_device": {
+                "nesting_mode": "list",
+                "max_items": 1,
+                "block": {"attributes": {"size": {"type": "number", "optional": true}}}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "backend_schemas": {
+    "s3": {"attributes": {"bucket": {"type": "string", "required": true}, "key": {"type": "string", "optional": true}}}
+  }
+}`
+
+var testInstanceType = cty.Object(map[string]cty.Type{
+	"id":                cty.String,
+	"ami":               cty.String,
+	"security_groups":   cty.Set(cty.String),
+	"tags":              cty.Map(cty.String),
+	"root_block_device": cty.List(cty.Object(map[string]cty.Type{"size": cty.Number})),
+})
+
+func testSchemas(t *testing.T) *schema.Schemas {
+	t.Helper()
+
+	s, err := schema.Parse([]byte(testSchemasJSON))
+	require.NoError(t, err)
+
+	return s
+}
+
+func testSchemaPlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	backendType := cty.Object(map[string]cty.Type{"bucket": cty.String, "key": cty.String})
+
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_instance.app",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action: plan.Action_CREATE,
+					Values: []*plan.DynamicValue{
+						requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+							"id":                cty.UnknownVal(cty.String),
+							"ami":               cty.StringVal("ami-web"),
+							"security_groups":   cty.SetVal([]cty.Value{cty.StringVal("web"), cty.StringVal("ssh")}),
+							"tags":              cty.NullVal(cty.Map(cty.String)),
+							"root_block_device": cty.ListValEmpty(cty.Object(map[string]cty.Type{"size": cty.Number})),
+						}), testInstanceType),
+					},
+				},
+			},
+		},
+		Backend: &plan.Backend{
+			Type: "s3",
+			Config: requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+				"bucket": cty.StringVal("web-state"),
+				"key":    cty.NullVal(cty.String),
+			}), backendType),
+		},
+	}
+}
+
+func TestEditDynamicValuesWithSchemas(t *testing.T) {
+	t.Parallel()
+
+	for _, singleDocument := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "tfplan")
+		// Renaming the "web" security group to "ssh" can only collapse into a single element if
+		// the value is edited as a set.
+		config := &Config{
+			TextEditorCmd:  sedEditor(t, `s/web/ssh/g`),
+			Format:         FormatJSON,
+			SingleDocument: singleDocument,
+			Schemas:        testSchemas(t),
+		}
+		p, err := editTFPlanOnlyMsgPack(path, config, testSchemaPlan(t))
+		require.NoError(t, err)
+
+		val, err := ctymsgpack.Unmarshal(p.GetResourceChanges()[0].GetChange().GetValues()[0].GetMsgpack(), testInstanceType)
+		require.NoError(t, err)
+		require.Equal(t, "ami-ssh", val.GetAttr("ami").AsString())
+		require.True(t, val.GetAttr("security_groups").Equals(cty.SetVal([]cty.Value{cty.StringVal("ssh")})).True())
+		require.True(t, val.GetAttr("tags").IsNull())
+
+		val, err = ctymsgpack.Unmarshal(p.GetBackend().GetConfig().GetMsgpack(), cty.Object(map[string]cty.Type{
+			"bucket": cty.String, "key": cty.String,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, "ssh-state", val.GetAttr("bucket").AsString())
+	}
+}
+
+func TestEditDynamicValuesWithSchemasErrors(t *testing.T) {
+	t.Parallel()
+
+	// A value that doesn't match its schema can't be decoded with it.
+	p := testSchemaPlan(t)
+	p.ResourceChanges[0].Change.Values[0] = requireDynamicValue(t, cty.StringVal("ami-web"), cty.String)
+	for _, singleDocument := range []bool{false, true} {
+		config := &Config{TextEditorCmd: "true", Format: FormatJSON, SingleDocument: singleDocument, Schemas: testSchemas(t)}
+		_, err := editTFPlanOnlyMsgPack(filepath.Join(t.TempDir(), "tfplan"), config, p)
+		require.ErrorContains(t, err, "unable to decode")
+	}
+
+	// Edits that don't match the schema are rejected rather than changing the value's type.
+	config := &Config{TextEditorCmd: sedEditor(t, `s/"ssh"/{}/`), Format: FormatJSON, Schemas: testSchemas(t)}
+	_, err := editTFPlanOnlyMsgPack(filepath.Join(t.TempDir(), "tfplan"), config, testSchemaPlan(t))
+	require.ErrorContains(t, err, "failed to encode edited dynamic value")
+}
+
+func TestDynamicValueSchemaType(t *testing.T) {
+	t.Parallel()
+
+	p := testSchemaPlan(t)
+	p.ResourceChanges = append(p.ResourceChanges, &plan.ResourceInstanceChange{
+		Addr:     "google_compute_instance.web",
+		Provider: `provider["registry.terraform.io/hashicorp/google"]`,
+		Change:   &plan.Change{Action: plan.Action_CREATE, Values: []*plan.DynamicValue{{Msgpack: []byte{0xc0}}}},
+	})
+	values := dynamicValues(p)
+	require.Len(t, values, 3)
+
+	require.Equal(t, cty.NilType, dynamicValueSchemaType(nil, values[0]))
+	require.True(t, testInstanceType.Equals(dynamicValueSchemaType(testSchemas(t), values[0])))
+	require.Equal(t, cty.NilType, dynamicValueSchemaType(testSchemas(t), values[1]))
+	require.True(t, cty.Object(map[string]cty.Type{
+		"bucket": cty.String, "key": cty.String,
+	}).Equals(dynamicValueSchemaType(testSchemas(t), values[2])))
+}
@@ -0,0 +1,417 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// sensitiveValue is a resource value along with the paths its schema declares as sensitive and
+// the sensitive paths that are recorded for it in the change.
+type sensitiveValue struct {
+	dynamicValue
+	// ty is the implied type of the resource's schema, or the type implied by the value's msgpack
+	// encoding when there is no schema for the resource.
+	ty          cty.Type
+	val         cty.Value
+	schemaPaths []cty.Path
+	// recorded is the before_sensitive_paths or after_sensitive_paths of the change.
+	recorded *[]*plan.Path
+}
+
+// sensitiveValues returns every resource value in the plan. Values of resources that the schemas
+// describe also have the paths their schema declares as sensitive, other values only have their
+// recorded sensitive paths.
+func sensitiveValues(schemas *schema.Schemas, p *plan.Plan) ([]sensitiveValue, error) {
+	values := []sensitiveValue{}
+	for _, d := range dynamicValues(p) {
+		if d.resource == nil {
+			continue
+		}
+
+		var recorded *[]*plan.Path
+		switch change := d.resource.GetChange(); d.keys[len(d.keys)-1] {
+		case "before":
+			recorded = &change.BeforeSensitivePaths
+		case "after":
+			recorded = &change.AfterSensitivePaths
+		default:
+			continue
+		}
+
+		block, err := dynamicValueSchema(schemas, d)
+		if err != nil {
+			fmt.Printf("warning: tfplan: %s: %s, only its recorded sensitive paths are used\n", d.key(), err)
+			block = nil
+		}
+
+		s := sensitiveValue{dynamicValue: d, recorded: recorded}
+		if block == nil {
+			if s.ty, err = impliedType(d.value.GetMsgpack()); err != nil {
+				return nil, pathError(d.key(), err)
+			}
+			if s.val, err = ctymsgpack.Unmarshal(d.value.GetMsgpack(), s.ty); err != nil {
+				return nil, pathError(d.key(), err)
+			}
+		} else {
+			s.ty = block.ImpliedType()
+			if s.val, err = ctymsgpack.Unmarshal(d.value.GetMsgpack(), s.ty); err != nil {
+				return nil, pathError(d.key(), fmt.Errorf("does not conform to the schema: %w", err))
+			}
+			s.schemaPaths = block.SensitivePaths(s.val)
+		}
+
+		values = append(values, s)
+	}
+
+	return values, nil
+}
+
+// recordedPaths returns the sensitive paths that are recorded for the value.
+func (s sensitiveValue) recordedPaths() ([]cty.Path, error) {
+	paths := []cty.Path{}
+	for _, p := range *s.recorded {
+		path, err := pathFromProto(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid sensitive path: %w", s.key(), err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// missingPaths returns the paths the schema declares as sensitive that aren't covered by the
+// recorded sensitive paths. A recorded path covers everything within it.
+func (s sensitiveValue) missingPaths() ([]cty.Path, error) {
+	recorded, err := s.recordedPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []cty.Path{}
+	for _, sp := range s.schemaPaths {
+		covered := false
+		for _, rp := range recorded {
+			if len(rp) <= len(sp) && rp.Equals(sp[:len(rp)]) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			missing = append(missing, sp)
+		}
+	}
+
+	return missing, nil
+}
+
+// ReportMissingSensitivePaths writes a report of every attribute that the provider schemas declare
+// as sensitive but that isn't recorded as sensitive in the plan.
+func (e *Editor) ReportMissingSensitivePaths(w io.Writer) error {
+	if e.Schemas == nil {
+		return errors.New("you must provide provider schemas to find sensitive attributes")
+	}
+
+	p, err := e.readPlan()
+	if err != nil {
+		return err
+	}
+
+	values, err := sensitiveValues(e.Schemas, p)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tVALUE\tPATH")
+	for _, s := range values {
+		missing, err := s.missingPaths()
+		if err != nil {
+			return err
+		}
+
+		for _, path := range missing {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", resourceInstanceChangeKey(s.resource), s.keys[len(s.keys)-1], formatCtyPath(path))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// AddSensitivePaths records every attribute that the provider schemas declare as sensitive, but
+// that isn't recorded as sensitive, in the before_sensitive_paths or after_sensitive_paths of its
+// change.
+func (e *Editor) AddSensitivePaths() error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		return addSensitivePaths(e.Schemas, p)
+	})
+}
+
+// RedactSensitiveValues replaces the value of every sensitive attribute with null, whether it's
+// declared as sensitive by the provider schemas or recorded as sensitive in the plan, along with the
+// value of every sensitive output, including in the plan's state snapshots. Without the provider schemas, or for resources they don't
+// describe, only the values recorded as sensitive are redacted. When there are selectors only the
+// values of the resource instances they select are redacted.
+func (e *Editor) RedactSensitiveValues() error {
+	kind, err := e.fileKind()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case jsonPlanFile:
+		return e.updateJSONPlan(func(doc map[string]any) error {
+			return redactJSONPlan(doc, e.Selectors)
+		})
+	case stateFile:
+		return e.updateState(func(s *state.State) error {
+			return redactState(e.Schemas, s, e.Selectors)
+		})
+	default:
+		return e.updatePlanDir(func(dir string, p *plan.Plan) error {
+			if err := redactSensitiveValues(e.Schemas, p, e.Selectors); err != nil {
+				return err
+			}
+
+			return updateStateFiles(dir, func(s *state.State) error {
+				return redactState(e.Schemas, s, e.Selectors)
+			})
+		})
+	}
+}
+
+func addSensitivePaths(schemas *schema.Schemas, p *plan.Plan) error {
+	if schemas == nil {
+		return errors.New("you must provide provider schemas to find sensitive attributes")
+	}
+
+	values, err := sensitiveValues(schemas, p)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range values {
+		missing, err := s.missingPaths()
+		if err != nil {
+			return err
+		}
+
+		for _, path := range missing {
+			pp, err := pathToProto(path)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", s.key(), formatCtyPath(path), err)
+			}
+			*s.recorded = append(*s.recorded, pp)
+		}
+	}
+
+	return nil
+}
+
+func redactSensitiveValues(schemas *schema.Schemas, p *plan.Plan, selectors []addrs.Selector) error {
+	// Sensitive outputs are replaced with null, as they are in the state snapshots. Outputs aren't
+	// resource instances, so selectors never select them.
+	if len(selectors) == 0 {
+		sensitive := map[string]bool{}
+		for _, o := range p.GetOutputChanges() {
+			sensitive[o.GetName()] = o.GetSensitive()
+		}
+
+		for _, d := range dynamicValues(p) {
+			if d.keys[0] == "output_changes" && sensitive[d.keys[1]] {
+				if err := redactOutputValue(d); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	values, err := sensitiveValues(schemas, p)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range values {
+		selected, err := selectsInstance(selectors, s.resource.GetAddr())
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.key(), err)
+		}
+		if !selected {
+			continue
+		}
+
+		recorded, err := s.recordedPaths()
+		if err != nil {
+			return err
+		}
+		paths := append(recorded, s.schemaPaths...)
+
+		val, err := cty.Transform(s.val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+			for _, sp := range paths {
+				if sensitivePathEquals(path, sp) {
+					return cty.NullVal(v.Type()), nil
+				}
+			}
+			return v, nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: unable to redact sensitive values: %w", s.key(), err)
+		}
+		if val.RawEquals(s.val) {
+			continue
+		}
+
+		s.value.Msgpack, err = ctymsgpack.Marshal(val, s.ty)
+		if err != nil {
+			return fmt.Errorf("%s: unable to encode redacted value: %w", s.key(), err)
+		}
+	}
+
+	return nil
+}
+
+// redactOutputValue replaces the value of a sensitive output with null. Unknown values are kept,
+// as they don't hold anything to redact.
+func redactOutputValue(d dynamicValue) error {
+	ty, err := impliedType(d.value.GetMsgpack())
+	if err != nil {
+		return pathError(d.key(), err)
+	}
+
+	val, err := ctymsgpack.Unmarshal(d.value.GetMsgpack(), ty)
+	if err != nil {
+		return pathError(d.key(), err)
+	}
+	if !val.IsKnown() || val.IsNull() {
+		return nil
+	}
+
+	d.value.Msgpack, err = ctymsgpack.Marshal(cty.NullVal(cty.DynamicPseudoType), cty.DynamicPseudoType)
+	if err != nil {
+		return fmt.Errorf("%s: unable to encode redacted value: %w", d.key(), err)
+	}
+
+	return nil
+}
+
+// sensitivePathEquals returns whether the paths are the same. The implied type of a value without a
+// schema has objects in place of maps, so an attribute step is the same as an index step with the
+// attribute's name.
+func sensitivePathEquals(a, b cty.Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	name := func(step cty.PathStep) (string, bool) {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			return s.Name, true
+		case cty.IndexStep:
+			if s.Key.Type() == cty.String && s.Key.IsKnown() && !s.Key.IsNull() {
+				return s.Key.AsString(), true
+			}
+		}
+		return "", false
+	}
+
+	for i := range a {
+		an, aok := name(a[i])
+		bn, bok := name(b[i])
+		switch {
+		case aok && bok:
+			if an != bn {
+				return false
+			}
+		case !cty.Path(a[i : i+1]).Equals(b[i : i+1]):
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectsInstance returns whether any of the selectors select the resource instance at the
+// address. Every instance is selected when there are no selectors.
+func selectsInstance(selectors []addrs.Selector, addr string) (bool, error) {
+	if len(selectors) == 0 {
+		return true, nil
+	}
+
+	a, err := addrs.Parse(addr)
+	if err != nil {
+		return false, err
+	}
+
+	return selectsAddress(selectors, a), nil
+}
+
+// selectsAddress is like selectsInstance for an address that has already been parsed.
+func selectsAddress(selectors []addrs.Selector, addr addrs.Address) bool {
+	return len(selectors) == 0 || slices.ContainsFunc(selectors, func(s addrs.Selector) bool {
+		return s.Match(addr)
+	})
+}
+
+// pathToProto converts a cty path to a plan path. Element keys are encoded as dynamic values, as
+// Terraform does.
+func pathToProto(path cty.Path) (*plan.Path, error) {
+	pp := &plan.Path{}
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			pp.Steps = append(pp.Steps, &plan.Path_Step{
+				Selector: &plan.Path_Step_AttributeName{AttributeName: s.Name},
+			})
+		case cty.IndexStep:
+			key, err := ctymsgpack.Marshal(s.Key, cty.DynamicPseudoType)
+			if err != nil {
+				return nil, err
+			}
+			pp.Steps = append(pp.Steps, &plan.Path_Step{
+				Selector: &plan.Path_Step_ElementKey{ElementKey: &plan.DynamicValue{Msgpack: key}},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported path step %T", step)
+		}
+	}
+
+	return pp, nil
+}
+
+// pathFromProto converts a plan path to a cty path.
+func pathFromProto(pp *plan.Path) (cty.Path, error) {
+	path := cty.Path{}
+	for _, step := range pp.GetSteps() {
+		switch s := step.GetSelector().(type) {
+		case *plan.Path_Step_AttributeName:
+			path = path.GetAttr(s.AttributeName)
+		case *plan.Path_Step_ElementKey:
+			b := s.ElementKey.GetMsgpack()
+			ty, err := impliedType(b)
+			if err != nil {
+				return nil, fmt.Errorf("invalid element key: %w", err)
+			}
+			key, err := ctymsgpack.Unmarshal(b, ty)
+			if err != nil {
+				return nil, fmt.Errorf("invalid element key: %w", err)
+			}
+			path = path.Index(key)
+		default:
+			return nil, errors.New("path step has no selector")
+		}
+	}
+
+	return path, nil
+}
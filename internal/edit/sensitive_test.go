@@ -0,0 +1,279 @@
+package edit
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+)
+
+const testSensitiveSchemasJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_db_instance": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "name": {"type": "string", "optional": true},
+              "password": {"type": "string", "optional": true, "sensitive": true}
+            },
+            "block_types": {
+              "user": {
+                "nesting_mode": "list",
+                "block": {"attributes": {"name": {"type": "string", "optional": true}, "token": {"type": "string", "optional": true, "sensitive": true}}}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+var testDBInstanceType = cty.Object(map[string]cty.Type{
+	"name":     cty.String,
+	"password": cty.String,
+	"user":     cty.List(cty.Object(map[string]cty.Type{"name": cty.String, "token": cty.String})),
+})
+
+func testSensitivePlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	user := func(name, token string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal(name), "token": cty.StringVal(token)})
+	}
+	val := func(password string) *plan.DynamicValue {
+		return requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+			"name":     cty.StringVal("db"),
+			"password": cty.StringVal(password),
+			"user":     cty.ListVal([]cty.Value{user("admin", "t0"), user("app", "t1")}),
+		}), testDBInstanceType)
+	}
+
+	// The before value records the password as sensitive and the whole user list, the after value
+	// records nothing.
+	password, err := pathToProto(cty.GetAttrPath("password"))
+	require.NoError(t, err)
+	users, err := pathToProto(cty.GetAttrPath("user"))
+	require.NoError(t, err)
+
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr:     "aws_db_instance.main",
+				Provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+				Change: &plan.Change{
+					Action:               plan.Action_UPDATE,
+					Values:               []*plan.DynamicValue{val("old"), val("new")},
+					BeforeSensitivePaths: []*plan.Path{password, users},
+				},
+			},
+		},
+	}
+}
+
+func testSensitiveSchemas(t *testing.T) *schema.Schemas {
+	t.Helper()
+
+	s, err := schema.Parse([]byte(testSensitiveSchemasJSON))
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestPathProtoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []cty.Path{
+		{},
+		cty.GetAttrPath("password"),
+		cty.GetAttrPath("user").IndexInt(1).GetAttr("token"),
+		cty.GetAttrPath("tags").IndexString("secret"),
+	} {
+		pp, err := pathToProto(path)
+		require.NoError(t, err)
+		got, err := pathFromProto(pp)
+		require.NoError(t, err)
+		require.True(t, path.Equals(got), formatCtyPath(path))
+	}
+}
+
+func TestReportMissingSensitivePaths(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testSensitivePlan(t), nil)
+	buf := &bytes.Buffer{}
+	require.NoError(t, New(&Config{PlanPath: planPath, Schemas: testSensitiveSchemas(t)}).ReportMissingSensitivePaths(buf))
+	require.Equal(t, strings.Join([]string{
+		"ADDRESS               VALUE  PATH",
+		"aws_db_instance.main  after  .password",
+		"aws_db_instance.main  after  .user[0].token",
+		"aws_db_instance.main  after  .user[1].token",
+		"",
+	}, "\n"), buf.String())
+
+	err := New(&Config{PlanPath: planPath}).ReportMissingSensitivePaths(buf)
+	require.ErrorContains(t, err, "you must provide provider schemas")
+}
+
+func TestAddSensitivePaths(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testSensitivePlan(t), nil)
+	dstPath := filepath.Join(t.TempDir(), "sensitive.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath, Schemas: testSensitiveSchemas(t)}).AddSensitivePaths())
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	change := p.GetResourceChanges()[0].GetChange()
+	require.Len(t, change.GetBeforeSensitivePaths(), 2)
+
+	paths := []string{}
+	for _, pp := range change.GetAfterSensitivePaths() {
+		path, err := pathFromProto(pp)
+		require.NoError(t, err)
+		paths = append(paths, formatCtyPath(path))
+	}
+	require.Equal(t, []string{".password", ".user[0].token", ".user[1].token"}, paths)
+
+	// Nothing is missing once the paths are added.
+	buf := &bytes.Buffer{}
+	require.NoError(t, New(&Config{PlanPath: dstPath, Schemas: testSensitiveSchemas(t)}).ReportMissingSensitivePaths(buf))
+	require.Equal(t, "ADDRESS  VALUE  PATH\n", buf.String())
+}
+
+func TestRedactSensitiveValues(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testSensitivePlan(t), nil)
+	dstPath := filepath.Join(t.TempDir(), "redacted.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath, Schemas: testSensitiveSchemas(t)}).RedactSensitiveValues())
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	values := p.GetResourceChanges()[0].GetChange().GetValues()
+
+	// The recorded path redacts the whole user list before the change.
+	before, err := ctymsgpack.Unmarshal(values[0].GetMsgpack(), testDBInstanceType)
+	require.NoError(t, err)
+	require.Equal(t, "db", before.GetAttr("name").AsString())
+	require.True(t, before.GetAttr("password").IsNull())
+	require.True(t, before.GetAttr("user").IsNull())
+
+	after, err := ctymsgpack.Unmarshal(values[1].GetMsgpack(), testDBInstanceType)
+	require.NoError(t, err)
+	require.True(t, after.GetAttr("password").IsNull())
+	for _, user := range after.GetAttr("user").AsValueSlice() {
+		require.False(t, user.GetAttr("name").IsNull())
+		require.True(t, user.GetAttr("token").IsNull())
+	}
+}
+
+func TestRedactSensitiveValuesWithoutSchemas(t *testing.T) {
+	t.Parallel()
+
+	otherSchemas, err := schema.Parse([]byte(`{"format_version": "1.0", "provider_schemas": {"registry.terraform.io/hashicorp/aws": {"resource_schemas": {}}}}`))
+	require.NoError(t, err)
+
+	for desc, schemas := range map[string]*schema.Schemas{
+		"no schemas":         nil,
+		"no resource schema": otherSchemas,
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			planPath := requirePlanFile(t, testSensitivePlan(t), nil)
+			dstPath := filepath.Join(t.TempDir(), "redacted.plan")
+			require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath, Schemas: schemas}).RedactSensitiveValues())
+
+			p, err := New(&Config{PlanPath: dstPath}).readPlan()
+			require.NoError(t, err)
+			values := p.GetResourceChanges()[0].GetChange().GetValues()
+
+			// Only the recorded paths are redacted.
+			before, err := ctymsgpack.Unmarshal(values[0].GetMsgpack(), testDBInstanceType)
+			require.NoError(t, err)
+			require.Equal(t, "db", before.GetAttr("name").AsString())
+			require.True(t, before.GetAttr("password").IsNull())
+			require.True(t, before.GetAttr("user").IsNull())
+
+			after, err := ctymsgpack.Unmarshal(values[1].GetMsgpack(), testDBInstanceType)
+			require.NoError(t, err)
+			require.Equal(t, "new", after.GetAttr("password").AsString())
+			require.Equal(t, "t0", after.GetAttr("user").Index(cty.NumberIntVal(0)).GetAttr("token").AsString())
+		})
+	}
+}
+
+func TestRedactSensitiveOutputChanges(t *testing.T) {
+	t.Parallel()
+
+	output := func(name string, sensitive bool, values ...cty.Value) *plan.OutputChange {
+		o := &plan.OutputChange{Name: name, Sensitive: sensitive, Change: &plan.Change{Action: plan.Action_UPDATE}}
+		for _, val := range values {
+			o.Change.Values = append(o.Change.Values, requireDynamicValue(t, val, cty.DynamicPseudoType))
+		}
+
+		return o
+	}
+
+	for desc, test := range map[string]struct {
+		selector string
+		password cty.Value
+	}{
+		"redacted": {password: cty.NullVal(cty.DynamicPseudoType)},
+		// Outputs aren't resource instances, so selectors never select them.
+		"selected": {selector: "aws_db_instance.*", password: cty.StringVal("hunter2")},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			planPath := requirePlanFile(t, &plan.Plan{
+				Version:          3,
+				TerraformVersion: "1.9.1",
+				OutputChanges: []*plan.OutputChange{
+					output("db_name", false, cty.StringVal("db"), cty.StringVal("db")),
+					output("db_password", true, cty.StringVal("hunter2"), cty.StringVal("hunter2")),
+					output("token", true, cty.StringVal("t0"), cty.UnknownVal(cty.String)),
+				},
+			}, nil)
+			config := &Config{PlanPath: planPath, DstPath: filepath.Join(t.TempDir(), "redacted.plan")}
+			if test.selector != "" {
+				sel, err := addrs.ParseSelector(test.selector)
+				require.NoError(t, err)
+				config.Selectors = []addrs.Selector{sel}
+			}
+			require.NoError(t, New(config).RedactSensitiveValues())
+
+			p, err := New(&Config{PlanPath: config.DstPath}).readPlan()
+			require.NoError(t, err)
+			values := map[string][]cty.Value{}
+			for _, o := range p.GetOutputChanges() {
+				for _, v := range o.GetChange().GetValues() {
+					val, err := ctymsgpack.Unmarshal(v.GetMsgpack(), cty.DynamicPseudoType)
+					require.NoError(t, err)
+					values[o.GetName()] = append(values[o.GetName()], val)
+				}
+			}
+
+			require.True(t, values["db_name"][1].RawEquals(cty.StringVal("db")))
+			for _, val := range values["db_password"] {
+				require.True(t, val.RawEquals(test.password), val.GoString())
+			}
+			// Unknown values don't hold anything to redact.
+			require.False(t, values["token"][1].IsKnown())
+		})
+	}
+}
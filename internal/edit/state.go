@@ -0,0 +1,552 @@
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+// fileKind is the kind of file the editor was given.
+type fileKind int
+
+const (
+	binaryPlanFile fileKind = iota
+	jsonPlanFile
+	stateFile
+)
+
+// stateFileNames are the state snapshots in a plan: the state after refresh and the state from the
+// previous run.
+var stateFileNames = []string{"tfstate", "tfstate-prev"}
+
+// detectFileKind returns whether the file at the path is a binary plan, the output of
+// `terraform show -json`, or a state file. Binary plans are zip files, so anything that isn't JSON
+// is assumed to be one.
+func detectFileKind(path string) (fileKind, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return binaryPlanFile, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return binaryPlanFile, nil
+			}
+			return binaryPlanFile, err
+		}
+
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		if b != '{' {
+			return binaryPlanFile, nil
+		}
+		break
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return binaryPlanFile, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.NewDecoder(f).Decode(&fields); err != nil {
+		return binaryPlanFile, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+
+	switch {
+	case fields["format_version"] != nil:
+		return jsonPlanFile, nil
+	case fields["lineage"] != nil, fields["version"] != nil:
+		return stateFile, nil
+	default:
+		return binaryPlanFile, fmt.Errorf("%s is neither a JSON plan nor a state file", path)
+	}
+}
+
+// fileKind returns the kind of file at PlanPath. It returns a binary plan if there's no path so
+// that the plan's own checks report it.
+func (e *Editor) fileKind() (fileKind, error) {
+	if e == nil || e.PlanPath == "" {
+		return binaryPlanFile, nil
+	}
+
+	kind, err := detectFileKind(e.PlanPath)
+	if err != nil {
+		return kind, fmt.Errorf("unable to read Terraform plan: %w", err)
+	}
+
+	return kind, nil
+}
+
+// updateState applies the update to the state file at PlanPath and writes it to DstPath.
+func (e *Editor) updateState(update func(s *state.State) error) error {
+	s, err := state.Load(e.PlanPath)
+	if err != nil {
+		return err
+	}
+
+	if err = update(s); err != nil {
+		return err
+	}
+
+	return e.writeState(s)
+}
+
+func (e *Editor) writeState(s *state.State) error {
+	b, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(e.DstPath, b, 0o644); err != nil {
+		return err
+	}
+	fmt.Println("write: " + e.DstPath)
+
+	return nil
+}
+
+// loadStateFiles reads the state snapshots of the unpacked plan in dir, keyed by file name.
+// Snapshots that the plan doesn't have are left out.
+func loadStateFiles(dir string) (map[string]*state.State, error) {
+	states := map[string]*state.State{}
+	for _, name := range stateFileNames {
+		s, err := state.Load(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		states[name] = s
+	}
+
+	return states, nil
+}
+
+// updateStateFiles applies the update to each state snapshot in the unpacked plan in dir.
+func updateStateFiles(dir string, update func(s *state.State) error) error {
+	for _, name := range stateFileNames {
+		path := filepath.Join(dir, name)
+		s, err := state.Load(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = update(s); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		b, err := s.Marshal()
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(path, b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// editState edits the state file at PlanPath and writes it to DstPath.
+func (e *Editor) editState() error {
+	dir, err := os.MkdirTemp("", "terraform-plan-edit")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(e.PlanPath))
+	if err = copyFile(e.PlanPath, path); err != nil {
+		return err
+	}
+
+	if err = editStateFile(e.Config, path); err != nil {
+		return err
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+
+	return e.writeState(s)
+}
+
+// stateInstanceDocument is the editing document of a resource instance in a state snapshot. The
+// sensitive attributes are readable paths, e.g. user[0].token.
+type stateInstanceDocument struct {
+	Attributes          json.RawMessage `json:"attributes"`
+	SensitiveAttributes []string        `json:"sensitive_attributes"`
+}
+
+// editStateFile edits the state snapshot at the path. Everything but the attributes of resource
+// instances, such as the serial, lineage, outputs and check results, is edited in a metadata
+// document. The attributes of each instance are edited in their own document, or all of them in
+// a single document keyed by instance. The snapshot is written back in Terraform's own format.
+func editStateFile(config *Config, path string) error {
+	s, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-plan-edit-state")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	keys := []string{}
+	docs := map[string]json.RawMessage{}
+	recordsSensitive := map[string]bool{}
+	for _, r := range s.Resources {
+		for _, i := range r.Instances {
+			if len(i.Attributes) == 0 {
+				continue
+			}
+
+			key, err := stateInstanceKey(r, i)
+			if err != nil {
+				return err
+			}
+			if _, ok := docs[key]; ok {
+				return fmt.Errorf("%s: instance is in the state more than once", key)
+			}
+
+			paths, err := state.UnmarshalPaths(i.SensitiveAttributes)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			doc := stateInstanceDocument{Attributes: i.Attributes, SensitiveAttributes: []string{}}
+			for _, p := range paths {
+				doc.SensitiveAttributes = append(doc.SensitiveAttributes, strings.TrimPrefix(formatCtyPath(p), "."))
+			}
+
+			docs[key], err = encodeJSON(doc)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			recordsSensitive[key] = len(i.SensitiveAttributes) > 0
+			i.Attributes, i.SensitiveAttributes = nil, nil
+		}
+	}
+
+	name := filepath.Base(path)
+	b, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+	b, err = editJSONDocument(dir, config, name+"-metadata", b)
+	if err != nil {
+		return err
+	}
+	edited, err := state.Parse(b)
+	if err != nil {
+		return fmt.Errorf("invalid %s metadata document: %w", name, err)
+	}
+
+	if config.SingleDocument {
+		err = editStateInstancesDocument(dir, config, name, keys, docs)
+	} else {
+		err = editStateInstances(dir, config, name, keys, docs)
+	}
+	if err != nil {
+		return err
+	}
+
+	instances := map[string]*state.Instance{}
+	for _, r := range edited.Resources {
+		for _, i := range r.Instances {
+			key, err := stateInstanceKey(r, i)
+			if err != nil {
+				return fmt.Errorf("invalid %s metadata document: %w", name, err)
+			}
+			instances[key] = i
+		}
+	}
+	for _, key := range keys {
+		i, ok := instances[key]
+		if !ok {
+			return fmt.Errorf("%s: the edited %s metadata no longer has the instance", key, name)
+		}
+
+		if err = setStateInstanceDocument(i, docs[key], recordsSensitive[key]); err != nil {
+			return fmt.Errorf("%s: invalid instance document: %w", key, err)
+		}
+	}
+
+	b, err = edited.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
+
+// editStateInstances edits the document of each instance in its own document.
+func editStateInstances(dir string, config *Config, name string, keys []string, docs map[string]json.RawMessage) error {
+	for _, key := range keys {
+		b, err := editJSONDocument(dir, config, name+"-"+strings.NewReplacer(" ", "-", "/", "-").Replace(key), docs[key])
+		if err != nil {
+			return err
+		}
+		docs[key] = b
+	}
+
+	return nil
+}
+
+// editStateInstancesDocument edits the documents of every instance in a single document keyed by
+// instance.
+func editStateInstancesDocument(dir string, config *Config, name string, keys []string, docs map[string]json.RawMessage) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	b, err := encodeJSON(docs)
+	if err != nil {
+		return err
+	}
+	b, err = editJSONDocument(dir, config, name+"-instances", b)
+	if err != nil {
+		return err
+	}
+
+	edited := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &edited); err != nil {
+		return fmt.Errorf("invalid %s instances document: %w", name, err)
+	}
+	for _, key := range keys {
+		doc, ok := edited[key]
+		if !ok {
+			return fmt.Errorf("invalid %s instances document: missing %s", name, key)
+		}
+		docs[key] = doc
+	}
+
+	return nil
+}
+
+// setStateInstanceDocument sets the attributes and sensitive attributes of the instance from its
+// edited document. Attributes are written with sorted keys like Terraform does, and instances
+// that didn't record sensitive attributes don't gain an empty list.
+func setStateInstanceDocument(i *state.Instance, raw json.RawMessage, recordsSensitive bool) error {
+	doc := stateInstanceDocument{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	attrs, err := decodeJSONPlanValue(doc.Attributes)
+	if err != nil {
+		return fmt.Errorf("invalid attributes: %w", err)
+	}
+	if _, ok := attrs.(map[string]any); !ok {
+		return errors.New("attributes must be an object")
+	}
+	if i.Attributes, err = encodeJSON(attrs); err != nil {
+		return err
+	}
+
+	if len(doc.SensitiveAttributes) == 0 && !recordsSensitive {
+		return nil
+	}
+	paths := []cty.Path{}
+	for _, s := range doc.SensitiveAttributes {
+		path, err := parseCtyPath(s)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+	i.SensitiveAttributes, err = state.MarshalPaths(paths)
+
+	return err
+}
+
+// stateResourceAddress returns the address of the resource in the state.
+func stateResourceAddress(r *state.Resource) string {
+	addr := r.Type + "." + r.Name
+	if r.Mode == "data" {
+		addr = "data." + addr
+	}
+	if r.Module != "" {
+		addr = r.Module + "." + addr
+	}
+
+	return addr
+}
+
+// stateInstanceAddress returns the address of the instance, formatted the same way as the
+// addresses in the plan.
+func stateInstanceAddress(r *state.Resource, i *state.Instance) (addrs.Address, error) {
+	addr, err := addrs.Parse(stateResourceAddress(r))
+	if err != nil {
+		return addrs.Address{}, err
+	}
+
+	switch k := i.IndexKey.(type) {
+	case nil:
+	case string:
+		addr.Key = addrs.StringKey(k)
+	default:
+		n, err := strconv.Atoi(fmt.Sprint(k))
+		if err != nil || n < 0 {
+			return addrs.Address{}, fmt.Errorf("%s: invalid index key %v", addr, k)
+		}
+		addr.Key = addrs.IntKey(n)
+	}
+
+	return addr, nil
+}
+
+// stateInstanceKey returns the key of the instance in editing documents and errors, which is its
+// address qualified with the deposed key if it's a deposed object.
+func stateInstanceKey(r *state.Resource, i *state.Instance) (string, error) {
+	addr, err := stateInstanceAddress(r, i)
+	if err != nil {
+		return "", err
+	}
+
+	key := addr.String()
+	if i.Deposed != "" {
+		key += " deposed " + i.Deposed
+	}
+
+	return key, nil
+}
+
+// redactState replaces the value of every sensitive attribute and output in the state with null.
+// Attributes are sensitive if they're recorded in sensitive_attributes or, when there are
+// schemas, declared as sensitive by their provider's schema. When there are selectors only the
+// attributes of the instances they select are redacted.
+func redactState(schemas *schema.Schemas, s *state.State, selectors []addrs.Selector) error {
+	for _, name := range mapkeys.Sorted(s.Outputs) {
+		if o := s.Outputs[name]; o.Sensitive && len(selectors) == 0 {
+			o.Value = json.RawMessage("null")
+		}
+	}
+
+	for _, r := range s.Resources {
+		var block *schema.Block
+		if schemas != nil {
+			var err error
+			block, err = resourceSchema(schemas, stateResourceAddress(r), r.Provider)
+			if err != nil {
+				return fmt.Errorf("%s: %w", stateResourceAddress(r), err)
+			}
+		}
+
+		for _, i := range r.Instances {
+			addr, err := stateInstanceAddress(r, i)
+			if err != nil {
+				return err
+			}
+			if !selectsAddress(selectors, addr) {
+				continue
+			}
+
+			key, err := stateInstanceKey(r, i)
+			if err != nil {
+				return err
+			}
+			if err := redactStateInstance(block, i); err != nil {
+				return pathError(key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func redactStateInstance(block *schema.Block, i *state.Instance) error {
+	if len(i.Attributes) == 0 {
+		return nil
+	}
+
+	paths, err := state.UnmarshalPaths(i.SensitiveAttributes)
+	if err != nil {
+		return err
+	}
+
+	if block != nil {
+		val, err := ctyjson.Unmarshal(i.Attributes, block.ImpliedType())
+		if err != nil {
+			return fmt.Errorf("attributes don't match the schema: %w", err)
+		}
+		paths = append(paths, block.SensitivePaths(val)...)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	attrs, err := decodeJSONPlanValue(i.Attributes)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		attrs = redactJSONPath(attrs, path)
+	}
+
+	i.Attributes, err = encodeJSON(attrs)
+
+	return err
+}
+
+// redactJSONPath returns the JSON value with the element at the path replaced with null.
+func redactJSONPath(v any, path cty.Path) any {
+	if len(path) == 0 {
+		return nil
+	}
+
+	switch s := path[0].(type) {
+	case cty.GetAttrStep:
+		if m, ok := v.(map[string]any); ok {
+			if elem, ok := m[s.Name]; ok {
+				m[s.Name] = redactJSONPath(elem, path[1:])
+			}
+		}
+	case cty.IndexStep:
+		switch {
+		case s.Key.Type() == cty.String:
+			if m, ok := v.(map[string]any); ok {
+				if elem, ok := m[s.Key.AsString()]; ok {
+					m[s.Key.AsString()] = redactJSONPath(elem, path[1:])
+				}
+			}
+		case s.Key.Type() == cty.Number:
+			l, ok := v.([]any)
+			i, accuracy := s.Key.AsBigFloat().Int64()
+			if ok && accuracy == 0 && i >= 0 && int(i) < len(l) {
+				l[i] = redactJSONPath(l[i], path[1:])
+			}
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,327 @@
+package edit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/state"
+)
+
+const testStateFile = `{
+  "version": 4,
+  "terraform_version": "1.9.1",
+  "serial": 7,
+  "lineage": "0b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a",
+  "outputs": {
+    "db_name": {
+      "value": "db",
+      "type": "string"
+    },
+    "db_password": {
+      "value": "hunter2",
+      "type": "string",
+      "sensitive": true
+    }
+  },
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {
+            "name": "db",
+            "password": "hunter2",
+            "user": [
+              {
+                "name": "admin",
+                "token": "t0"
+              },
+              {
+                "name": "app",
+                "token": "t1"
+              }
+            ]
+          },
+          "sensitive_attributes": [
+            [
+              {
+                "type": "get_attr",
+                "value": "user"
+              },
+              {
+                "type": "index",
+                "value": {
+                  "value": 0,
+                  "type": "number"
+                }
+              }
+            ]
+          ]
+        }
+      ]
+    }
+  ],
+  "check_results": null
+}
+`
+
+func requireStateFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	require.NoError(t, os.WriteFile(path, []byte(testStateFile), 0o644))
+
+	return path
+}
+
+func requireReadState(t *testing.T, path string) *state.State {
+	t.Helper()
+
+	s, err := state.Load(path)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestDetectFileKind(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		path string
+		kind fileKind
+		err  string
+	}{
+		"binary plan": {
+			path: requirePlanFile(t, &plan.Plan{Version: 3, TerraformVersion: "1.9.1"}, nil),
+			kind: binaryPlanFile,
+		},
+		"json plan": {
+			path: requireJSONPlanFile(t),
+			kind: jsonPlanFile,
+		},
+		"state": {
+			path: requireStateFile(t),
+			kind: stateFile,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			kind, err := detectFileKind(test.path)
+			require.NoError(t, err)
+			require.Equal(t, test.kind, kind)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "other.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"other": true}`), 0o644))
+	_, err := detectFileKind(path)
+	require.ErrorContains(t, err, "is neither a JSON plan nor a state file")
+}
+
+func TestRedactState(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		schemas    bool
+		selector   string
+		attributes string
+		password   string
+	}{
+		// Without schemas only the recorded sensitive attributes are redacted.
+		"recorded": {
+			attributes: `{"name": "db", "password": "hunter2", "user": [null, {"name": "app", "token": "t1"}]}`,
+			password:   `null`,
+		},
+		"schemas": {
+			schemas:    true,
+			attributes: `{"name": "db", "password": null, "user": [null, {"name": "app", "token": null}]}`,
+			password:   `null`,
+		},
+		// Outputs aren't resource instances, so selectors never select them.
+		"selected": {
+			schemas:    true,
+			selector:   `aws_db_instance.*`,
+			attributes: `{"name": "db", "password": null, "user": [null, {"name": "app", "token": null}]}`,
+			password:   `"hunter2"`,
+		},
+		"not selected": {
+			schemas:    true,
+			selector:   `module.db`,
+			attributes: `{"name": "db", "password": "hunter2", "user": [{"name": "admin", "token": "t0"}, {"name": "app", "token": "t1"}]}`,
+			password:   `"hunter2"`,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Config{PlanPath: requireStateFile(t), DstPath: filepath.Join(t.TempDir(), "redacted.tfstate")}
+			if test.schemas {
+				config.Schemas = testSensitiveSchemas(t)
+			}
+			if test.selector != "" {
+				sel, err := addrs.ParseSelector(test.selector)
+				require.NoError(t, err)
+				config.Selectors = []addrs.Selector{sel}
+			}
+			require.NoError(t, New(config).RedactSensitiveValues())
+
+			s := requireReadState(t, config.DstPath)
+			require.EqualValues(t, 7, s.Serial)
+			require.JSONEq(t, test.password, string(s.Outputs["db_password"].Value))
+			require.JSONEq(t, `"db"`, string(s.Outputs["db_name"].Value))
+			require.JSONEq(t, test.attributes, string(s.Resources[0].Instances[0].Attributes))
+		})
+	}
+}
+
+func TestRedactSensitiveValuesInPlanStates(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testSensitivePlan(t), map[string]string{
+		"tfstate":      testStateFile,
+		"tfstate-prev": testStateFile,
+	})
+	dstPath := filepath.Join(t.TempDir(), "redacted.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath, Schemas: testSensitiveSchemas(t)}).RedactSensitiveValues())
+
+	files := requireZipFiles(t, dstPath)
+	for _, name := range stateFileNames {
+		s, err := state.Parse([]byte(files[name]))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name": "db", "password": null, "user": [null, {"name": "app", "token": null}]}`, string(s.Resources[0].Instances[0].Attributes), name)
+	}
+}
+
+func TestEditState(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		editorCmd      string
+		singleDocument bool
+		err            string
+	}{
+		"instance": {
+			editorCmd: sedEditor(t, `s/hunter2/changeme/`, `s/"serial":.7,/"serial":8,/`, `s/user\[0\]/user[1].token/`),
+		},
+		"single document": {
+			editorCmd:      sedEditor(t, `s/hunter2/changeme/`, `s/"serial":.7,/"serial":8,/`, `s/user\[0\]/user[1].token/`),
+			singleDocument: true,
+		},
+		"invalid sensitive attribute": {
+			editorCmd: sedEditor(t, `s/user\[0\]/user[0/`),
+			err:       `aws_db_instance.main: invalid instance document: invalid path "user[0": unterminated index`,
+		},
+		"removed instance": {
+			editorCmd: sedEditor(t, `s/"main"/"other"/`),
+			err:       "aws_db_instance.main: the edited terraform.tfstate metadata no longer has the instance",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Config{
+				PlanPath:       requireStateFile(t),
+				DstPath:        filepath.Join(t.TempDir(), "edited.tfstate"),
+				TextEditorCmd:  test.editorCmd,
+				Format:         FormatJSON,
+				SingleDocument: test.singleDocument,
+			}
+			err := New(config).Edit()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			s := requireReadState(t, config.DstPath)
+			require.EqualValues(t, 8, s.Serial)
+			require.JSONEq(t, `"changeme"`, string(s.Outputs["db_password"].Value))
+			i := s.Resources[0].Instances[0]
+			require.JSONEq(t, `{"name": "db", "password": "changeme", "user": [{"name": "admin", "token": "t0"}, {"name": "app", "token": "t1"}]}`, string(i.Attributes))
+			require.JSONEq(t, `[[{"type": "get_attr", "value": "user"}, {"type": "index", "value": {"value": 1, "type": "number"}}, {"type": "get_attr", "value": "token"}]]`, string(i.SensitiveAttributes))
+
+			// The state is written back in Terraform's own format.
+			b, err := os.ReadFile(config.DstPath)
+			require.NoError(t, err)
+			b2, err := s.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, string(b2), string(b))
+		})
+	}
+}
+
+func TestEditStateUnchanged(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		PlanPath:      requireStateFile(t),
+		DstPath:       filepath.Join(t.TempDir(), "edited.tfstate"),
+		TextEditorCmd: "true",
+		Format:        FormatYAML,
+	}
+	require.NoError(t, New(config).Edit())
+
+	b, err := os.ReadFile(config.DstPath)
+	require.NoError(t, err)
+	require.Equal(t, testStateFile, string(b))
+}
+
+func TestEditPlanState(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, &plan.Plan{Version: 3, TerraformVersion: "1.9.1"}, map[string]string{
+		"tfstate": testStateFile,
+	})
+	dstPath := filepath.Join(t.TempDir(), "edited.plan")
+	require.NoError(t, New(&Config{
+		PlanPath:      planPath,
+		DstPath:       dstPath,
+		TextEditorCmd: sedEditor(t, `s/hunter2/changeme/`),
+		Format:        FormatJSON,
+	}).Edit())
+
+	s, err := state.Parse([]byte(requireZipFiles(t, dstPath)["tfstate"]))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name": "db", "password": "changeme", "user": [{"name": "admin", "token": "t0"}, {"name": "app", "token": "t1"}]}`, string(s.Resources[0].Instances[0].Attributes))
+}
+
+func TestStateInstanceKey(t *testing.T) {
+	t.Parallel()
+
+	for expected, test := range map[string]struct {
+		r *state.Resource
+		i *state.Instance
+	}{
+		`aws_instance.web`: {
+			r: &state.Resource{Mode: "managed", Type: "aws_instance", Name: "web"},
+			i: &state.Instance{},
+		},
+		`module.app["blue"].data.aws_ami.ubuntu[1]`: {
+			r: &state.Resource{Module: `module.app["blue"]`, Mode: "data", Type: "aws_ami", Name: "ubuntu"},
+			i: &state.Instance{IndexKey: json.Number("1")},
+		},
+		// String keys are escaped the way Terraform writes them in plans.
+		`aws_instance.web["$${x}-%%{y}"] deposed 00000001`: {
+			r: &state.Resource{Mode: "managed", Type: "aws_instance", Name: "web"},
+			i: &state.Instance{IndexKey: "${x}-%{y}", Deposed: "00000001"},
+		},
+	} {
+		key, err := stateInstanceKey(test.r, test.i)
+		require.NoError(t, err)
+		require.Equal(t, expected, key)
+	}
+
+	_, err := stateInstanceKey(&state.Resource{Mode: "managed", Type: "aws_instance", Name: "web"}, &state.Instance{IndexKey: json.Number("1.5")})
+	require.EqualError(t, err, "aws_instance.web: invalid index key 1.5")
+}
@@ -0,0 +1,187 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// AddTargets adds the addresses to the plan's target_addrs. Every address must target at least
+// one resource instance in resource_changes.
+func (e *Editor) AddTargets(targets []string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		return addTargets(p, targets)
+	})
+}
+
+// RemoveTargets removes the addresses from the plan's target_addrs.
+func (e *Editor) RemoveTargets(targets []string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		var err error
+		p.TargetAddrs, err = removeAddrs(p.GetTargetAddrs(), targets, addrs.Parse)
+
+		return err
+	})
+}
+
+// AddForceReplace adds the resource instance addresses to the plan's force_replace_addrs. When
+// replace is set the action of each instance is changed to DELETE_THEN_CREATE with the
+// REPLACE_BY_REQUEST reason, as if the plan had been created with -replace.
+func (e *Editor) AddForceReplace(replaceAddrs []string, replace bool) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		return addForceReplace(p, replaceAddrs, replace)
+	})
+}
+
+// RemoveForceReplace removes the addresses from the plan's force_replace_addrs. The actions of
+// the instances are not changed.
+func (e *Editor) RemoveForceReplace(replaceAddrs []string) error {
+	return e.updatePlan(func(p *plan.Plan) error {
+		var err error
+		p.ForceReplaceAddrs, err = removeAddrs(p.GetForceReplaceAddrs(), replaceAddrs, parseForceReplaceAddr)
+
+		return err
+	})
+}
+
+func addTargets(p *plan.Plan, targets []string) error {
+	errs := []error{}
+	for _, t := range targets {
+		target, err := addrs.Parse(t)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if len(matchingResourceChanges(p, target.TargetContains)) == 0 {
+			errs = append(errs, fmt.Errorf("target %s does not match any resource change", target))
+			continue
+		}
+
+		p.TargetAddrs = appendAddr(p.GetTargetAddrs(), target.String())
+	}
+
+	return errors.Join(errs...)
+}
+
+func addForceReplace(p *plan.Plan, replaceAddrs []string, replace bool) error {
+	errs := []error{}
+	for _, r := range replaceAddrs {
+		addr, err := parseForceReplaceAddr(r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		changes := matchingResourceChanges(p, addr.Equal)
+		if len(changes) == 0 {
+			errs = append(errs, fmt.Errorf("force-replace address %s does not match any resource change", addr))
+			continue
+		}
+
+		if replace {
+			for _, c := range changes {
+				if err := replaceByRequest(c); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+
+		p.ForceReplaceAddrs = appendAddr(p.GetForceReplaceAddrs(), addr.String())
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseForceReplaceAddr parses an address that can be given to -replace, which must be a managed
+// resource instance.
+func parseForceReplaceAddr(s string) (addrs.Address, error) {
+	addr, err := addrs.ParseResourceInstance(s)
+	if err != nil {
+		return addrs.Address{}, err
+	}
+
+	if addr.Resource.Mode != addrs.ManagedResourceMode {
+		return addrs.Address{}, fmt.Errorf("invalid address %q: only managed resources can be replaced", s)
+	}
+
+	return addr, nil
+}
+
+// matchingResourceChanges returns the current, i.e. not deposed, resource changes whose address
+// matches.
+func matchingResourceChanges(p *plan.Plan, match func(addrs.Address) bool) []*plan.ResourceInstanceChange {
+	changes := []*plan.ResourceInstanceChange{}
+	for _, c := range p.GetResourceChanges() {
+		if c.GetDeposedKey() != "" {
+			continue
+		}
+
+		addr, err := addrs.Parse(c.GetAddr())
+		if err != nil || !match(addr) {
+			continue
+		}
+
+		changes = append(changes, c)
+	}
+
+	return changes
+}
+
+// replaceByRequest changes an update or no-op to the replacement that -replace would have planned.
+func replaceByRequest(c *plan.ResourceInstanceChange) error {
+	change := c.GetChange()
+	switch change.GetAction() {
+	case plan.Action_DELETE_THEN_CREATE, plan.Action_CREATE_THEN_DELETE:
+	case plan.Action_UPDATE:
+		change.Action = plan.Action_DELETE_THEN_CREATE
+	case plan.Action_NOOP:
+		// A no-op only has the prior value, which is also the planned value.
+		change.Action = plan.Action_DELETE_THEN_CREATE
+		if len(change.GetValues()) == 1 {
+			change.Values = append(change.Values, change.GetValues()[0])
+		}
+	default:
+		return fmt.Errorf("%s: cannot replace an instance with a %s action", c.GetAddr(), change.GetAction())
+	}
+
+	c.ActionReason = plan.ResourceInstanceActionReason_REPLACE_BY_REQUEST
+
+	return nil
+}
+
+// appendAddr appends the address unless it is already present.
+func appendAddr(existing []string, addr string) []string {
+	if slices.Contains(existing, addr) {
+		return existing
+	}
+
+	return append(existing, addr)
+}
+
+// removeAddrs removes the addresses from the existing addresses, comparing their canonical forms.
+func removeAddrs(existing []string, remove []string, parse func(string) (addrs.Address, error)) ([]string, error) {
+	errs := []error{}
+	for _, r := range remove {
+		addr, err := parse(r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		i := slices.IndexFunc(existing, func(s string) bool {
+			e, err := addrs.Parse(s)
+			return err == nil && e.Equal(addr)
+		})
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("%s is not in the plan", addr))
+			continue
+		}
+		existing = slices.Delete(existing, i, i+1)
+	}
+
+	return existing, errors.Join(errs...)
+}
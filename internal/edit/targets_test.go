@@ -0,0 +1,138 @@
+package edit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func testTargetsPlan() *plan.Plan {
+	return &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.9.1",
+		TargetAddrs:      []string{`module.app["blue"]`},
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr: `module.app["blue"].aws_instance.web[0]`,
+				Change: &plan.Change{
+					Action: plan.Action_NOOP,
+					Values: []*plan.DynamicValue{{Msgpack: []byte("before")}},
+				},
+			},
+			{
+				Addr: `module.app["blue"].aws_instance.web[1]`,
+				Change: &plan.Change{
+					Action: plan.Action_UPDATE,
+					Values: []*plan.DynamicValue{{Msgpack: []byte("before")}, {Msgpack: []byte("after")}},
+				},
+			},
+			{
+				Addr:   `data.aws_ami.ubuntu`,
+				Change: &plan.Change{Action: plan.Action_READ},
+			},
+			{
+				Addr:   `aws_instance.db`,
+				Change: &plan.Change{Action: plan.Action_CREATE},
+			},
+		},
+	}
+}
+
+func TestAddAndRemoveTargets(t *testing.T) {
+	t.Parallel()
+
+	planPath := requirePlanFile(t, testTargetsPlan(), nil)
+	dstPath := filepath.Join(t.TempDir(), "targeted.plan")
+	require.NoError(t, New(&Config{PlanPath: planPath, DstPath: dstPath}).AddTargets([]string{
+		`module.app`, `module.app["blue"].aws_instance.web`, `data.aws_ami.ubuntu`, `module.app["blue"]`,
+	}))
+
+	p, err := New(&Config{PlanPath: dstPath}).readPlan()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`module.app["blue"]`, `module.app`, `module.app["blue"].aws_instance.web`, `data.aws_ami.ubuntu`,
+	}, p.GetTargetAddrs())
+
+	removedPath := filepath.Join(t.TempDir(), "untargeted.plan")
+	require.NoError(t, New(&Config{PlanPath: dstPath, DstPath: removedPath}).RemoveTargets([]string{
+		`module.app["blue"]`, `data.aws_ami.ubuntu`,
+	}))
+	p, err = New(&Config{PlanPath: removedPath}).readPlan()
+	require.NoError(t, err)
+	require.Equal(t, []string{`module.app`, `module.app["blue"].aws_instance.web`}, p.GetTargetAddrs())
+}
+
+func TestAddTargetsErrors(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		target string
+		err    string
+	}{
+		"invalid":         {target: `aws_instance`, err: `invalid address "aws_instance"`},
+		"invalid key":     {target: `aws_instance.web[-1]`, err: `invalid instance key at offset 17`},
+		"no module match": {target: `module.app["green"]`, err: `target module.app["green"] does not match any resource change`},
+		"no key match":    {target: `aws_instance.db[0]`, err: `target aws_instance.db[0] does not match any resource change`},
+		"mode mismatch":   {target: `aws_ami.ubuntu`, err: `target aws_ami.ubuntu does not match any resource change`},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorContains(t, addTargets(testTargetsPlan(), []string{test.target}), test.err)
+		})
+	}
+}
+
+func TestAddForceReplace(t *testing.T) {
+	t.Parallel()
+
+	p := testTargetsPlan()
+	require.NoError(t, addForceReplace(p, []string{
+		`module.app["blue"].aws_instance.web[0]`, `module.app["blue"].aws_instance.web[1]`,
+	}, true))
+	require.Equal(t, []string{
+		`module.app["blue"].aws_instance.web[0]`, `module.app["blue"].aws_instance.web[1]`,
+	}, p.GetForceReplaceAddrs())
+
+	for _, c := range p.GetResourceChanges()[:2] {
+		require.Equal(t, plan.Action_DELETE_THEN_CREATE, c.GetChange().GetAction())
+		require.Equal(t, plan.ResourceInstanceActionReason_REPLACE_BY_REQUEST, c.GetActionReason())
+		require.Len(t, c.GetChange().GetValues(), 2)
+	}
+
+	p = testTargetsPlan()
+	require.NoError(t, addForceReplace(p, []string{`aws_instance.db`}, false))
+	require.Equal(t, plan.Action_CREATE, p.GetResourceChanges()[3].GetChange().GetAction())
+	require.NoError(t, removeForceReplaceAddrs(p, `aws_instance.db`))
+	require.Empty(t, p.GetForceReplaceAddrs())
+	require.ErrorContains(t, removeForceReplaceAddrs(p, `aws_instance.db`), "aws_instance.db is not in the plan")
+
+	for desc, test := range map[string]struct {
+		addr    string
+		replace bool
+		err     string
+	}{
+		"no key":     {addr: `module.app["blue"].aws_instance.web`, err: "does not match any resource change"},
+		"data":       {addr: `data.aws_ami.ubuntu`, err: "only managed resources can be replaced"},
+		"module":     {addr: `module.app["blue"]`, err: "must be a resource instance address"},
+		"wildcard":   {addr: `aws_instance.db[*]`, err: "must be a resource instance address"},
+		"no match":   {addr: `aws_instance.db[0]`, err: "does not match any resource change"},
+		"bad action": {addr: `aws_instance.db`, replace: true, err: "cannot replace an instance with a CREATE action"},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorContains(t, addForceReplace(testTargetsPlan(), []string{test.addr}, test.replace), test.err)
+		})
+	}
+}
+
+func removeForceReplaceAddrs(p *plan.Plan, addr string) error {
+	var err error
+	p.ForceReplaceAddrs, err = removeAddrs(p.GetForceReplaceAddrs(), []string{addr}, parseForceReplaceAddr)
+
+	return err
+}
@@ -0,0 +1,132 @@
+package edit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// unknownFields are the encoded fields of a message and its nested messages that aren't in the
+// vendored schema, keyed by the location of each message, e.g.
+// tfplan.resource_changes["aws_instance.web"].change. Elements of lists are located by their
+// address or name when they have one, so that the fields stay with their message when the list
+// is reordered or other elements are removed.
+type unknownFields map[string]protoreflect.RawFields
+
+// collectUnknownFields returns the unknown fields of the message and every message nested in it.
+// Plans from newer versions of Terraform can have fields that we don't know about, which are lost
+// whenever the plan goes through prototext or protojson while it's edited.
+func collectUnknownFields(m proto.Message) unknownFields {
+	fields := unknownFields{}
+	walkMessages(m.ProtoReflect(), "tfplan", func(path string, m protoreflect.Message) {
+		if raw := m.GetUnknown(); len(raw) > 0 {
+			fields[path] = raw
+		}
+	})
+
+	return fields
+}
+
+// restore attaches the unknown fields to the messages at the same locations in the message. It
+// returns the locations of messages that no longer exist, whose unknown fields couldn't be kept.
+func (u unknownFields) restore(m proto.Message) []string {
+	restored := map[string]bool{}
+	walkMessages(m.ProtoReflect(), "tfplan", func(path string, m protoreflect.Message) {
+		if raw, ok := u[path]; ok {
+			m.SetUnknown(raw)
+			restored[path] = true
+		}
+	})
+
+	dropped := []string{}
+	for path := range u {
+		if !restored[path] {
+			dropped = append(dropped, path)
+		}
+	}
+	sort.Strings(dropped)
+
+	return dropped
+}
+
+// paths returns the locations of the messages that have unknown fields in order.
+func (u unknownFields) paths() []string {
+	paths := make([]string, 0, len(u))
+	for path := range u {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// walkMessages calls fn with the message and every message that is set within it, along with
+// their locations.
+func walkMessages(m protoreflect.Message, path string, fn func(path string, m protoreflect.Message)) {
+	fn(path, m)
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := path + "." + string(fd.Name())
+
+		switch {
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			keys := []protoreflect.MapKey{}
+			v.Map().Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				walkMessages(v.Map().Get(k).Message(), fmt.Sprintf("%s[%q]", name, k.String()), fn)
+			}
+		case fd.IsList() && fd.Message() != nil:
+			seen := map[string]int{}
+			for i := 0; i < v.List().Len(); i++ {
+				elem := v.List().Get(i).Message()
+				key := listElementKey(elem, i)
+				// Elements with the same address are told apart by their order.
+				if seen[key]++; seen[key] > 1 {
+					key = fmt.Sprintf("%s#%d", key, seen[key])
+				}
+				walkMessages(elem, name+"["+key+"]", fn)
+			}
+		case !fd.IsMap() && !fd.IsList() && fd.Message() != nil:
+			walkMessages(v.Message(), name, fn)
+		}
+
+		return true
+	})
+}
+
+// listElementKey returns the key that locates the message in its list: its quoted address or name
+// if it has one, otherwise its index.
+func listElementKey(m protoreflect.Message, i int) string {
+	var key string
+	switch e := m.Interface().(type) {
+	case *plan.ResourceInstanceChange:
+		key = resourceInstanceChangeKey(e)
+	case *plan.DeferredResourceInstanceChange:
+		key = resourceInstanceChangeKey(e.GetChange())
+	case *plan.OutputChange:
+		key = e.GetName()
+	case *plan.CheckResults:
+		key = e.GetConfigAddr()
+	case *plan.CheckResults_ObjectResult:
+		key = e.GetObjectAddr()
+	case *plan.ActionInvocationInstance:
+		key = actionInvocationKey(e)
+	case *plan.DeferredActionInvocation:
+		key = actionInvocationKey(e.GetActionInvocation())
+	}
+
+	if key == "" {
+		return strconv.Itoa(i)
+	}
+
+	return strconv.Quote(key)
+}
@@ -0,0 +1,118 @@
+package edit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func unknownField(num protowire.Number, value string) protoreflect.RawFields {
+	b := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(b, []byte(value))
+}
+
+// testUnknownFieldsPlan returns a plan from a newer Terraform with fields that aren't in the
+// vendored schema.
+func testUnknownFieldsPlan(t *testing.T) *plan.Plan {
+	t.Helper()
+
+	rc := &plan.ResourceInstanceChange{
+		Addr: "aws_instance.web",
+		Change: &plan.Change{
+			Action: plan.Action_CREATE,
+			Values: []*plan.DynamicValue{requireDynamicValue(t, cty.StringVal("ami-web"), cty.DynamicPseudoType)},
+		},
+	}
+	rc.ProtoReflect().SetUnknown(unknownField(99, "resource"))
+	rc.Change.ProtoReflect().SetUnknown(unknownField(98, "change"))
+	rc.Change.Values[0].ProtoReflect().SetUnknown(unknownField(97, "value"))
+
+	variable := requireDynamicValue(t, cty.StringVal("secret"), cty.DynamicPseudoType)
+	variable.ProtoReflect().SetUnknown(unknownField(96, "variable"))
+
+	p := &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.14.0",
+		Variables:        map[string]*plan.DynamicValue{"token": variable},
+		ResourceChanges:  []*plan.ResourceInstanceChange{rc},
+	}
+	p.ProtoReflect().SetUnknown(unknownField(100, "plan"))
+
+	return p
+}
+
+func TestCollectAndRestoreUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	unknown := collectUnknownFields(testUnknownFieldsPlan(t))
+	require.Equal(t, []string{
+		"tfplan",
+		`tfplan.resource_changes["aws_instance.web"]`,
+		`tfplan.resource_changes["aws_instance.web"].change`,
+		`tfplan.resource_changes["aws_instance.web"].change.values[0]`,
+		`tfplan.variables["token"]`,
+	}, unknown.paths())
+
+	// Copies keep unknown fields.
+	p := &plan.Plan{}
+	require.NoError(t, copy(testUnknownFieldsPlan(t), p))
+	require.Equal(t, unknown, collectUnknownFields(p))
+
+	// Unknown fields can only be restored to messages that still exist.
+	p = testUnknownFieldsPlan(t)
+	p.ProtoReflect().SetUnknown(nil)
+	p.ResourceChanges[0].Change = &plan.Change{Action: plan.Action_CREATE}
+	require.Equal(t, []string{`tfplan.resource_changes["aws_instance.web"].change.values[0]`}, unknown.restore(p))
+	require.Equal(t, protoreflect.RawFields(unknownField(100, "plan")), p.ProtoReflect().GetUnknown())
+	require.Equal(t, protoreflect.RawFields(unknownField(98, "change")), p.GetResourceChanges()[0].GetChange().ProtoReflect().GetUnknown())
+
+	// Unknown fields stay with their message when the list is reordered.
+	p = testUnknownFieldsPlan(t)
+	p.ResourceChanges[0].ProtoReflect().SetUnknown(nil)
+	db := &plan.ResourceInstanceChange{Addr: "aws_instance.db", Change: &plan.Change{Action: plan.Action_CREATE}}
+	p.ResourceChanges = append([]*plan.ResourceInstanceChange{db}, p.ResourceChanges...)
+	require.Empty(t, unknown.restore(p))
+	require.Empty(t, p.GetResourceChanges()[0].ProtoReflect().GetUnknown())
+	require.Equal(t, protoreflect.RawFields(unknownField(99, "resource")), p.GetResourceChanges()[1].ProtoReflect().GetUnknown())
+
+	// And are dropped with their message rather than attached to another one.
+	p.ResourceChanges = p.ResourceChanges[:1]
+	require.Equal(t, []string{
+		`tfplan.resource_changes["aws_instance.web"]`,
+		`tfplan.resource_changes["aws_instance.web"].change`,
+		`tfplan.resource_changes["aws_instance.web"].change.values[0]`,
+	}, unknown.restore(p))
+	require.Empty(t, p.GetResourceChanges()[0].ProtoReflect().GetUnknown())
+}
+
+func TestEditTFPlanPreservesUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	for _, singleDocument := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "tfplan")
+		orig, err := proto.Marshal(testUnknownFieldsPlan(t))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, orig, 0o644))
+
+		config := &Config{TextEditorCmd: sedEditor(t, `s/ami-web/ami-app/`), Format: FormatJSON, SingleDocument: singleDocument}
+		require.NoError(t, editTFPlan(config, path))
+
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		p := &plan.Plan{}
+		require.NoError(t, proto.Unmarshal(b, p))
+		require.Equal(t, collectUnknownFields(testUnknownFieldsPlan(t)), collectUnknownFields(p))
+
+		val, _, err := decodeDynamicValueJSON(p.GetResourceChanges()[0].GetChange().GetValues()[0].GetMsgpack(), cty.NilType)
+		require.NoError(t, err)
+		require.Contains(t, string(val), "ami-app")
+	}
+}
@@ -0,0 +1,165 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
+)
+
+// resourceMetaArguments are the arguments and blocks that Terraform handles itself, which are
+// allowed in a resource block regardless of its schema.
+var resourceMetaArguments = map[string]bool{
+	"count":       true,
+	"depends_on":  true,
+	"for_each":    true,
+	"provider":    true,
+	"lifecycle":   true,
+	"connection":  true,
+	"provisioner": true,
+}
+
+// validatePlan returns an error for each resource and backend value, and each generated config,
+// that doesn't conform to its schema. Values the schemas don't describe are not validated.
+func validatePlan(schemas *schema.Schemas, p *plan.Plan) error {
+	if schemas == nil {
+		return nil
+	}
+
+	errs := []error{}
+	for _, d := range dynamicValues(p) {
+		block, err := dynamicValueSchema(schemas, d)
+		if err != nil || block == nil {
+			continue
+		}
+
+		val, err := ctymsgpack.Unmarshal(d.value.GetMsgpack(), block.ImpliedType())
+		if err != nil {
+			errs = append(errs, pathError(d.key(), fmt.Errorf("does not conform to the schema: %w", err)))
+			continue
+		}
+
+		for _, err := range block.Validate(val) {
+			errs = append(errs, pathError(d.key(), err))
+		}
+	}
+
+	for _, c := range generatedConfigs(p) {
+		block, err := resourceSchema(schemas, c.addr, c.provider)
+		if err != nil {
+			continue
+		}
+
+		errs = append(errs, validateGeneratedConfig(block, c)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// pathError prefixes the error with the key of the value and the path within it, if it has one.
+func pathError(key string, err error) error {
+	var pathErr cty.PathError
+	if errors.As(err, &pathErr) {
+		return fmt.Errorf("%s%s: %w", key, formatCtyPath(pathErr.Path), err)
+	}
+
+	return fmt.Errorf("%s: %w", key, err)
+}
+
+// validateGeneratedConfig returns an error for each argument or block in the generated config that
+// the resource's schema doesn't allow, including computed attributes that can't be configured.
+func validateGeneratedConfig(block *schema.Block, c generatedConfig) []error {
+	file, diags := hclsyntax.ParseConfig([]byte(c.change.GetGeneratedConfig()), c.addr+".tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return []error{fmt.Errorf("%s: generated config: %w", c.addr, diags)}
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	errs := []error{}
+	for _, b := range body.Blocks {
+		if b.Type == "resource" || b.Type == "data" {
+			errs = append(errs, validateConfigBody(block, b.Body, c.addr, "", true)...)
+		}
+	}
+
+	return errs
+}
+
+func validateConfigBody(block *schema.Block, body *hclsyntax.Body, addr string, prefix string, resource bool) []error {
+	if block == nil {
+		return nil
+	}
+
+	errs := []error{}
+	errorf := func(rng hcl.Range, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: generated config line %d: %s", addr, rng.Start.Line, fmt.Sprintf(format, args...)))
+	}
+
+	for _, name := range mapkeys.Sorted(body.Attributes) {
+		attr := body.Attributes[name]
+		if resource && resourceMetaArguments[name] {
+			continue
+		}
+
+		a, ok := block.Attributes[name]
+		switch {
+		case !ok:
+			errorf(attr.SrcRange, "unexpected attribute %q", prefix+name)
+		case a.Computed && !a.Optional && !a.Required:
+			errorf(attr.SrcRange, "attribute %q is computed and cannot be set", prefix+name)
+		}
+	}
+
+	for _, name := range mapkeys.Sorted(block.Attributes) {
+		if _, ok := body.Attributes[name]; !ok && block.Attributes[name].Required {
+			errorf(body.SrcRange, "missing required attribute %q", prefix+name)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, b := range body.Blocks {
+		if resource && resourceMetaArguments[b.Type] {
+			continue
+		}
+
+		nb, ok := block.BlockTypes[b.Type]
+		if !ok {
+			errorf(b.TypeRange, "unexpected block %q", prefix+b.Type)
+			continue
+		}
+
+		counts[b.Type]++
+		if len(b.Labels) > 0 {
+			errorf(b.TypeRange, "block %q cannot have labels", prefix+b.Type)
+		}
+		errs = append(errs, validateConfigBody(nb.Block, b.Body, addr, prefix+b.Type+".", false)...)
+	}
+
+	for _, name := range mapkeys.Sorted(block.BlockTypes) {
+		nb, n := block.BlockTypes[name], counts[name]
+		maxItems := nb.MaxItems
+		if nb.NestingMode == schema.NestingSingle || nb.NestingMode == schema.NestingGroup {
+			maxItems = 1
+		}
+
+		switch {
+		case nb.MinItems > 0 && n < nb.MinItems:
+			errorf(body.SrcRange, "at least %d %q blocks are required, found %d", nb.MinItems, prefix+name, n)
+		case maxItems > 0 && n > maxItems:
+			errorf(body.SrcRange, "at most %d %q blocks are allowed, found %d", maxItems, prefix+name, n)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,93 @@
+package edit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/protobuf/proto"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func TestValidatePlan(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validatePlan(nil, testSchemaPlan(t)))
+	require.NoError(t, validatePlan(testSchemas(t), testSchemaPlan(t)))
+
+	device := cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(8)})
+	p := testSchemaPlan(t)
+	p.ResourceChanges[0].Change.Values[0] = requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+		"id":                cty.StringVal("i-1234"),
+		"ami":               cty.NullVal(cty.String),
+		"security_groups":   cty.NullVal(cty.Set(cty.String)),
+		"tags":              cty.NullVal(cty.Map(cty.String)),
+		"root_block_device": cty.ListVal([]cty.Value{device, device}),
+	}), testInstanceType)
+	p.ResourceChanges[0].Change.GeneratedConfig = strings.Join([]string{
+		`resource "aws_instance" "app" {`,
+		`  provider = aws.west`,
+		`  id       = "i-1234"`,
+		`  bogus    = true`,
+		`  root_block_device {`,
+		`    size  = 8`,
+		`    iops  = 100`,
+		`  }`,
+		`  root_block_device {}`,
+		`  ebs_block_device {}`,
+		`  lifecycle {`,
+		`    ignore_changes = [tags]`,
+		`  }`,
+		`}`,
+	}, "\n")
+	// Values for resources that aren't in the schemas can't be validated.
+	p.ResourceChanges = append(p.ResourceChanges, &plan.ResourceInstanceChange{
+		Addr:     "google_compute_instance.web",
+		Provider: `provider["registry.terraform.io/hashicorp/google"]`,
+		Change:   &plan.Change{Action: plan.Action_CREATE, Values: []*plan.DynamicValue{{Msgpack: []byte{0xc0}}}},
+	})
+	p.Backend.Config = requireDynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+		"bucket": cty.StringVal("web-state"),
+		"region": cty.StringVal("us-east-1"),
+	}), cty.Object(map[string]cty.Type{"bucket": cty.String, "region": cty.String}))
+
+	err := validatePlan(testSchemas(t), p)
+	require.Error(t, err)
+	require.Equal(t, strings.Join([]string{
+		`resource_changes["aws_instance.app"].after.ami: required attribute is null`,
+		`resource_changes["aws_instance.app"].after.root_block_device: at most 1 blocks are allowed, found 2`,
+		`backend_config["region"]: does not conform to the schema: unsupported attribute`,
+		`aws_instance.app: generated config line 4: unexpected attribute "bogus"`,
+		`aws_instance.app: generated config line 3: attribute "id" is computed and cannot be set`,
+		`aws_instance.app: generated config line 1: missing required attribute "ami"`,
+		`aws_instance.app: generated config line 7: unexpected attribute "root_block_device.iops"`,
+		`aws_instance.app: generated config line 10: unexpected block "ebs_block_device"`,
+		`aws_instance.app: generated config line 1: at most 1 "root_block_device" blocks are allowed, found 2`,
+	}, "\n"), err.Error())
+}
+
+func TestEditTFPlanValidatesSchemas(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tfplan")
+	orig, err := proto.Marshal(testSchemaPlan(t))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, orig, 0o644))
+
+	config := &Config{TextEditorCmd: sedEditor(t, `s/"ami-web"/null/`), Format: FormatJSON, Schemas: testSchemas(t)}
+	err = editTFPlan(config, path)
+	require.ErrorContains(t, err, "edited plan does not conform to the provider schemas")
+	require.ErrorContains(t, err, `resource_changes["aws_instance.app"].after.ami: required attribute is null`)
+
+	// Nothing is written when the edited plan is invalid.
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, orig, bytes)
+
+	config.TextEditorCmd = sedEditor(t, `s/"ami-web"/"ami-app"/`)
+	require.NoError(t, editTFPlan(config, path))
+}
@@ -0,0 +1,499 @@
+package edit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// unknownValueMarker is the key of the JSON object we use to represent an unknown value in an
+// editing document. cty's JSON encoding has no notion of unknown values so we substitute
+// {"$unknown": true} for each one and convert it back into an unknown value of the correct type
+// when the document is decoded. Real map keys and attribute names that start with "$" are escaped
+// with another "$", so that {"$$unknown": true} is a map with a "$unknown" key and can't be
+// mistaken for the marker. Unknown values that Terraform has refined, e.g. as not null or with a
+// known string prefix, carry their refinements in the marker, i.e.
+// {"$unknown": true, "refinements": {"not_null": true, "string_prefix": "ami-"}}.
+const unknownValueMarker = "$unknown"
+
+// unknownRefinements are the refinements of an unknown value that cty preserves when it encodes
+// the value as msgpack.
+type unknownRefinements struct {
+	NotNull          bool         `json:"not_null,omitempty"`
+	StringPrefix     string       `json:"string_prefix,omitempty"`
+	NumberLowerBound *numberBound `json:"number_lower_bound,omitempty"`
+	NumberUpperBound *numberBound `json:"number_upper_bound,omitempty"`
+	LengthLowerBound int          `json:"length_lower_bound,omitempty"`
+	LengthUpperBound *int         `json:"length_upper_bound,omitempty"`
+}
+
+type numberBound struct {
+	Value     json.RawMessage `json:"value"`
+	Inclusive bool            `json:"inclusive"`
+}
+
+// marshalValueJSON encodes a cty value as JSON in the same way as ctyjson.Marshal, except that
+// unknown values are encoded as an unknown value marker rather than returning an error.
+func marshalValueJSON(val cty.Value, ty cty.Type) ([]byte, error) {
+	return marshalValueJSONPath(val, ty, nil)
+}
+
+func marshalValueJSONPath(val cty.Value, ty cty.Type, path cty.Path) (json.RawMessage, error) {
+	if val.IsMarked() {
+		return nil, path.NewErrorf("value has marks, so it cannot be serialized as JSON")
+	}
+
+	if ty == cty.DynamicPseudoType && val.Type() != cty.DynamicPseudoType {
+		typeJSON, err := ctyjson.MarshalType(val.Type())
+		if err != nil {
+			return nil, path.NewErrorf("failed to serialize type: %s", err)
+		}
+
+		value, err := marshalValueJSONPath(val, val.Type(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeJSON(struct {
+			Value json.RawMessage `json:"value"`
+			Type  json.RawMessage `json:"type"`
+		}{value, typeJSON})
+	}
+
+	if !val.IsKnown() {
+		return marshalUnknownValueJSON(val, path)
+	}
+
+	if val.IsNull() {
+		return json.RawMessage("null"), nil
+	}
+
+	switch {
+	case ty == cty.String:
+		return encodeJSON(val.AsString())
+	case ty.IsPrimitiveType():
+		return ctyjson.Marshal(val, ty)
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		elems := []json.RawMessage{}
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			ety := v.Type()
+			switch {
+			case ty.IsListType(), ty.IsSetType():
+				ety = ty.ElementType()
+			case ty.IsTupleType():
+				i, _ := k.AsBigFloat().Int64()
+				ety = ty.TupleElementType(int(i))
+			}
+
+			elem, err := marshalValueJSONPath(v, ety, append(path, cty.IndexStep{Key: k}))
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+
+		return encodeJSON(elems)
+	case ty.IsMapType():
+		elems := map[string]json.RawMessage{}
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elem, err := marshalValueJSONPath(v, ty.ElementType(), append(path, cty.IndexStep{Key: k}))
+			if err != nil {
+				return nil, err
+			}
+			elems[escapeValueKey(k.AsString())] = elem
+		}
+
+		return encodeJSON(elems)
+	case ty.IsObjectType():
+		attrs := map[string]json.RawMessage{}
+		for name, aty := range ty.AttributeTypes() {
+			attr, err := marshalValueJSONPath(val.GetAttr(name), aty, append(path, cty.GetAttrStep{Name: name}))
+			if err != nil {
+				return nil, err
+			}
+			attrs[escapeValueKey(name)] = attr
+		}
+
+		return encodeJSON(attrs)
+	default:
+		return ctyjson.Marshal(val, ty)
+	}
+}
+
+// unmarshalValueJSON decodes JSON that was encoded with marshalValueJSON into a cty value of the
+// given type. Any unknown value markers are decoded as unknown values.
+func unmarshalValueJSON(buf []byte, ty cty.Type) (cty.Value, error) {
+	return unmarshalValueJSONPath(json.RawMessage(buf), ty, nil)
+}
+
+func unmarshalValueJSONPath(raw json.RawMessage, ty cty.Type, path cty.Path) (cty.Value, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return cty.NilVal, path.NewErrorf("value is required")
+	}
+
+	if bytes.Equal(raw, []byte("null")) {
+		return cty.NullVal(ty), nil
+	}
+
+	if marker, ok := unknownValueMarkerOf(raw); ok {
+		return unmarshalUnknownValueJSON(marker, ty, path)
+	}
+
+	switch {
+	case ty == cty.DynamicPseudoType:
+		dyn := struct {
+			Value json.RawMessage `json:"value"`
+			Type  json.RawMessage `json:"type"`
+		}{}
+		if err := decodeJSON(raw, &dyn, path); err != nil {
+			return cty.NilVal, err
+		}
+
+		if len(dyn.Type) == 0 {
+			return cty.NilVal, path.NewErrorf("missing type in dynamic value")
+		}
+		dty, err := ctyjson.UnmarshalType(dyn.Type)
+		if err != nil {
+			return cty.NilVal, path.NewErrorf("invalid type in dynamic value: %s", err)
+		}
+		if len(dyn.Value) == 0 {
+			return cty.NilVal, path.NewErrorf("missing value in dynamic value")
+		}
+
+		return unmarshalValueJSONPath(dyn.Value, dty, path)
+	case ty.IsPrimitiveType():
+		val, err := ctyjson.Unmarshal(raw, ty)
+		if err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+
+		return val, nil
+	case ty.IsListType(), ty.IsSetType():
+		elems := []json.RawMessage{}
+		if err := decodeJSON(raw, &elems, path); err != nil {
+			return cty.NilVal, err
+		}
+
+		vals := []cty.Value{}
+		for i, elem := range elems {
+			val, err := unmarshalValueJSONPath(elem, ty.ElementType(), append(path, cty.IndexStep{Key: cty.NumberIntVal(int64(i))}))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals = append(vals, val)
+		}
+
+		switch {
+		case ty.IsListType() && len(vals) == 0:
+			return cty.ListValEmpty(ty.ElementType()), nil
+		case ty.IsListType():
+			return cty.ListVal(vals), nil
+		case len(vals) == 0:
+			return cty.SetValEmpty(ty.ElementType()), nil
+		default:
+			return cty.SetVal(vals), nil
+		}
+	case ty.IsTupleType():
+		elems := []json.RawMessage{}
+		if err := decodeJSON(raw, &elems, path); err != nil {
+			return cty.NilVal, err
+		}
+
+		etys := ty.TupleElementTypes()
+		if len(elems) != len(etys) {
+			return cty.NilVal, path.NewErrorf("expected %d elements in tuple, got %d", len(etys), len(elems))
+		}
+
+		vals := []cty.Value{}
+		for i, elem := range elems {
+			val, err := unmarshalValueJSONPath(elem, etys[i], append(path, cty.IndexStep{Key: cty.NumberIntVal(int64(i))}))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals = append(vals, val)
+		}
+
+		return cty.TupleVal(vals), nil
+	case ty.IsMapType():
+		elems, err := decodeJSONObject(raw, path)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		if len(elems) == 0 {
+			return cty.MapValEmpty(ty.ElementType()), nil
+		}
+
+		vals := map[string]cty.Value{}
+		for k, elem := range elems {
+			val, err := unmarshalValueJSONPath(elem, ty.ElementType(), append(path, cty.IndexStep{Key: cty.StringVal(k)}))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[k] = val
+		}
+
+		return cty.MapVal(vals), nil
+	case ty.IsObjectType():
+		attrs, err := decodeJSONObject(raw, path)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		names := []string{}
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !ty.HasAttribute(name) {
+				return cty.NilVal, path.NewErrorf("unsupported attribute %q", name)
+			}
+		}
+
+		if len(ty.AttributeTypes()) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+
+		vals := map[string]cty.Value{}
+		for name, aty := range ty.AttributeTypes() {
+			attr, ok := attrs[name]
+			if !ok {
+				vals[name] = cty.NullVal(aty)
+				continue
+			}
+
+			val, err := unmarshalValueJSONPath(attr, aty, append(path, cty.GetAttrStep{Name: name}))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[name] = val
+		}
+
+		return cty.ObjectVal(vals), nil
+	default:
+		val, err := ctyjson.Unmarshal(raw, ty)
+		if err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+
+		return val, nil
+	}
+}
+
+// marshalUnknownValueJSON encodes an unknown value as an unknown value marker with the value's
+// refinements.
+func marshalUnknownValueJSON(val cty.Value, path cty.Path) (json.RawMessage, error) {
+	marker := struct {
+		Unknown     bool                `json:"$unknown"`
+		Refinements *unknownRefinements `json:"refinements,omitempty"`
+	}{Unknown: true}
+
+	if val.Type() == cty.DynamicPseudoType {
+		return encodeJSON(marker)
+	}
+
+	rng := val.Range()
+	r := &unknownRefinements{NotNull: rng.DefinitelyNotNull()}
+	switch ty := val.Type(); {
+	case ty == cty.String:
+		r.StringPrefix = rng.StringPrefix()
+	case ty == cty.Number:
+		bound := func(v cty.Value, inclusive bool) (*numberBound, error) {
+			if !v.IsKnown() || v == cty.NegativeInfinity || v == cty.PositiveInfinity {
+				return nil, nil
+			}
+			raw, err := ctyjson.Marshal(v, cty.Number)
+			if err != nil {
+				return nil, path.NewError(err)
+			}
+
+			return &numberBound{Value: raw, Inclusive: inclusive}, nil
+		}
+
+		var err error
+		if r.NumberLowerBound, err = bound(rng.NumberLowerBound()); err != nil {
+			return nil, err
+		}
+		if r.NumberUpperBound, err = bound(rng.NumberUpperBound()); err != nil {
+			return nil, err
+		}
+	case ty.IsCollectionType():
+		r.LengthLowerBound = rng.LengthLowerBound()
+		if upper := rng.LengthUpperBound(); upper != math.MaxInt {
+			r.LengthUpperBound = &upper
+		}
+	}
+
+	if *r != (unknownRefinements{}) {
+		marker.Refinements = r
+	}
+
+	return encodeJSON(marker)
+}
+
+// unmarshalUnknownValueJSON decodes an unknown value marker into an unknown value of the given type
+// with the marker's refinements.
+func unmarshalUnknownValueJSON(marker map[string]json.RawMessage, ty cty.Type, path cty.Path) (cty.Value, error) {
+	raw, ok := marker["refinements"]
+	if !ok {
+		return cty.UnknownVal(ty), nil
+	}
+
+	r := unknownRefinements{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return cty.NilVal, path.NewErrorf("invalid unknown value refinements: %s", err)
+	}
+	if r == (unknownRefinements{}) {
+		return cty.UnknownVal(ty), nil
+	}
+	if ty == cty.DynamicPseudoType {
+		return cty.NilVal, path.NewErrorf("unknown values of an unknown type can't be refined")
+	}
+
+	refinementErrorf := func(name string, format string, args ...any) (cty.Value, error) {
+		return cty.NilVal, path.NewErrorf("invalid unknown value refinement %q: %s", name, fmt.Sprintf(format, args...))
+	}
+
+	b := cty.UnknownVal(ty).Refine()
+	if r.NotNull {
+		b = b.NotNull()
+	}
+
+	if r.StringPrefix != "" {
+		if ty != cty.String {
+			return refinementErrorf("string_prefix", "%s values don't have a prefix", ty.FriendlyName())
+		}
+		b = b.StringPrefixFull(r.StringPrefix)
+	}
+
+	if r.NumberLowerBound != nil || r.NumberUpperBound != nil {
+		if ty != cty.Number {
+			return refinementErrorf("number_lower_bound", "%s values don't have number bounds", ty.FriendlyName())
+		}
+
+		lower, upper := cty.NegativeInfinity, cty.PositiveInfinity
+		lowerInc, upperInc := false, false
+		var err error
+		if r.NumberLowerBound != nil {
+			if lower, err = ctyjson.Unmarshal(r.NumberLowerBound.Value, cty.Number); err != nil || lower.IsNull() {
+				return refinementErrorf("number_lower_bound", "value must be a number")
+			}
+			lowerInc = r.NumberLowerBound.Inclusive
+		}
+		if r.NumberUpperBound != nil {
+			if upper, err = ctyjson.Unmarshal(r.NumberUpperBound.Value, cty.Number); err != nil || upper.IsNull() {
+				return refinementErrorf("number_upper_bound", "value must be a number")
+			}
+			upperInc = r.NumberUpperBound.Inclusive
+		}
+
+		ok := lower.LessThanOrEqualTo(upper)
+		if lowerInc != upperInc {
+			ok = lower.LessThan(upper)
+		}
+		if ok.False() {
+			return refinementErrorf("number_lower_bound", "must not be greater than the upper bound")
+		}
+		if r.NumberLowerBound != nil {
+			b = b.NumberRangeLowerBound(lower, lowerInc)
+		}
+		if r.NumberUpperBound != nil {
+			b = b.NumberRangeUpperBound(upper, upperInc)
+		}
+	}
+
+	if r.LengthLowerBound != 0 || r.LengthUpperBound != nil {
+		if !ty.IsCollectionType() {
+			return refinementErrorf("length_lower_bound", "%s values don't have a length", ty.FriendlyName())
+		}
+		if r.LengthLowerBound < 0 {
+			return refinementErrorf("length_lower_bound", "must not be negative")
+		}
+		if r.LengthUpperBound != nil && *r.LengthUpperBound < r.LengthLowerBound {
+			return refinementErrorf("length_upper_bound", "must not be less than the lower bound")
+		}
+		b = b.CollectionLengthLowerBound(r.LengthLowerBound)
+		if r.LengthUpperBound != nil {
+			b = b.CollectionLengthUpperBound(*r.LengthUpperBound)
+		}
+	}
+
+	return b.NewValue(), nil
+}
+
+// unknownValueMarkerOf returns the unknown value marker that the raw JSON is, if it is one.
+func unknownValueMarkerOf(raw json.RawMessage) (map[string]json.RawMessage, bool) {
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, false
+	}
+
+	marker := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &marker); err != nil {
+		return nil, false
+	}
+	for k := range marker {
+		if k != unknownValueMarker && k != "refinements" {
+			return nil, false
+		}
+	}
+
+	return marker, bytes.Equal(bytes.TrimSpace(marker[unknownValueMarker]), []byte("true"))
+}
+
+// escapeValueKey escapes a map key or attribute name that starts with "$" by adding another "$".
+func escapeValueKey(k string) string {
+	if strings.HasPrefix(k, "$") {
+		return "$" + k
+	}
+
+	return k
+}
+
+// decodeJSONObject decodes a JSON object whose keys were escaped with escapeValueKey.
+func decodeJSONObject(raw json.RawMessage, path cty.Path) (map[string]json.RawMessage, error) {
+	escaped := map[string]json.RawMessage{}
+	if err := decodeJSON(raw, &escaped, path); err != nil {
+		return nil, err
+	}
+
+	elems := map[string]json.RawMessage{}
+	for k, elem := range escaped {
+		if strings.HasPrefix(k, "$") && !strings.HasPrefix(k, "$$") {
+			return nil, path.NewErrorf("invalid key %q, keys that start with $ must be written as %q", k, "$"+k)
+		}
+		elems[strings.TrimPrefix(k, "$")] = elem
+	}
+
+	return elems, nil
+}
+
+func encodeJSON(v any) (json.RawMessage, error) {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func decodeJSON(raw json.RawMessage, v any, path cty.Path) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return path.NewError(fmt.Errorf("invalid JSON: %w", err))
+	}
+
+	return nil
+}
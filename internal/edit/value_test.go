@@ -0,0 +1,191 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+)
+
+func TestValueJSONUnknownRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{
+		"id":   cty.String,
+		"name": cty.String,
+		"tags": cty.Map(cty.String),
+		"ips":  cty.List(cty.String),
+		"size": cty.Number,
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.UnknownVal(cty.String),
+		"name": cty.StringVal("<web>"),
+		"tags": cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+		"ips":  cty.UnknownVal(cty.List(cty.String)),
+		"size": cty.NullVal(cty.Number),
+	})
+
+	b, err := marshalValueJSON(val, ty)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": {"$unknown": true},
+		"ips": {"$unknown": true},
+		"name": "<web>",
+		"size": null,
+		"tags": {"env": "dev"}
+	}`, string(b))
+
+	got, err := unmarshalValueJSON(b, ty)
+	require.NoError(t, err)
+	require.True(t, val.RawEquals(got), got.GoString())
+}
+
+func TestValueJSONRefinedUnknownRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{
+		"id":    cty.String,
+		"port":  cty.Number,
+		"ips":   cty.List(cty.String),
+		"extra": cty.DynamicPseudoType,
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.UnknownVal(cty.String).Refine().NotNull().StringPrefixFull("i-").NewValue(),
+		"port":  cty.UnknownVal(cty.Number).Refine().NumberRangeLowerBound(cty.NumberIntVal(1), true).NumberRangeUpperBound(cty.NumberIntVal(65536), false).NewValue(),
+		"ips":   cty.UnknownVal(cty.List(cty.String)).Refine().CollectionLengthLowerBound(1).CollectionLengthUpperBound(2).NewValue(),
+		"extra": cty.UnknownVal(cty.String).RefineNotNull(),
+	})
+
+	b, err := marshalValueJSON(val, ty)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"extra": {"value": {"$unknown": true, "refinements": {"not_null": true}}, "type": "string"},
+		"id": {"$unknown": true, "refinements": {"not_null": true, "string_prefix": "i-"}},
+		"ips": {"$unknown": true, "refinements": {"length_lower_bound": 1, "length_upper_bound": 2}},
+		"port": {"$unknown": true, "refinements": {"number_lower_bound": {"value": 1, "inclusive": true}, "number_upper_bound": {"value": 65536, "inclusive": false}}}
+	}`, string(b))
+
+	got, err := unmarshalValueJSON(b, ty)
+	require.NoError(t, err)
+	require.True(t, val.RawEquals(got), got.GoString())
+
+	// A refined unknown is encoded the same way after an untouched edit.
+	in := []byte{0xc7, 0x03, 0x0c, 0x81, 0x01, 0xc2}
+	decoded, err := ctymsgpack.Unmarshal(in, cty.String)
+	require.NoError(t, err)
+	b, err = marshalValueJSON(decoded, cty.String)
+	require.NoError(t, err)
+	got, err = unmarshalValueJSON(b, cty.String)
+	require.NoError(t, err)
+	out, err := ctymsgpack.Marshal(got, cty.String)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+
+	for in, expected := range map[string]string{
+		`{"id": {"$unknown": true, "refinements": {"nullable": false}}}`:                                                        `invalid unknown value refinements: json: unknown field "nullable"`,
+		`{"port": {"$unknown": true, "refinements": {"string_prefix": "1"}}}`:                                                   `invalid unknown value refinement "string_prefix": number values don't have a prefix`,
+		`{"port": {"$unknown": true, "refinements": {"number_lower_bound": {"value": 2}, "number_upper_bound": {"value": 1}}}}`: `invalid unknown value refinement "number_lower_bound": must not be greater than the upper bound`,
+		`{"ips": {"$unknown": true, "refinements": {"length_lower_bound": 2, "length_upper_bound": 1}}}`:                        `invalid unknown value refinement "length_upper_bound": must not be less than the lower bound`,
+		`{"extra": {"$unknown": true, "refinements": {"not_null": true}}}`:                                                      "unknown values of an unknown type can't be refined",
+	} {
+		_, err := unmarshalValueJSON([]byte(in), ty)
+		require.ErrorContains(t, err, expected, in)
+	}
+}
+
+func TestValueJSONUnknownImpliedType(t *testing.T) {
+	t.Parallel()
+
+	// Planned values for a CREATE are usually decoded with an implied type, which means unknown
+	// values are of the DynamicPseudoType.
+	ty := cty.Object(map[string]cty.Type{
+		"arn":  cty.String,
+		"id":   cty.DynamicPseudoType,
+		"tags": cty.DynamicPseudoType,
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"arn":  cty.StringVal("arn:aws:iam::123456789012:user/web"),
+		"id":   cty.DynamicVal,
+		"tags": cty.NullVal(cty.DynamicPseudoType),
+	})
+	in, err := ctymsgpack.Marshal(val, ty)
+	require.NoError(t, err)
+
+	typ, err := ctymsgpack.ImpliedType(in)
+	require.NoError(t, err)
+	require.True(t, ty.Equals(typ), typ.GoString())
+	decoded, err := ctymsgpack.Unmarshal(in, typ)
+	require.NoError(t, err)
+
+	b, err := marshalValueJSON(decoded, typ)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"arn": "arn:aws:iam::123456789012:user/web",
+		"id": {"$unknown": true},
+		"tags": null
+	}`, string(b))
+
+	got, err := unmarshalValueJSON([]byte(`{"arn":"redacted","id":{"$unknown":true},"tags":null}`), typ)
+	require.NoError(t, err)
+	out, err := ctymsgpack.Marshal(got, typ)
+	require.NoError(t, err)
+
+	expected, err := ctymsgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"arn":  cty.StringVal("redacted"),
+		"id":   cty.DynamicVal,
+		"tags": cty.NullVal(cty.DynamicPseudoType),
+	}), ty)
+	require.NoError(t, err)
+	require.Equal(t, expected, out)
+}
+
+func TestValueJSONEscapesKeys(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{
+		"tags":  cty.Map(cty.Bool),
+		"extra": cty.Map(cty.Bool),
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"tags":  cty.MapVal(map[string]cty.Value{"$unknown": cty.True}),
+		"extra": cty.UnknownVal(cty.Map(cty.Bool)),
+	})
+
+	b, err := marshalValueJSON(val, ty)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"extra": {"$unknown": true},
+		"tags": {"$$unknown": true}
+	}`, string(b))
+
+	got, err := unmarshalValueJSON(b, ty)
+	require.NoError(t, err)
+	require.True(t, val.RawEquals(got), got.GoString())
+
+	_, err = unmarshalValueJSON([]byte(`{"tags":{"$env":true}}`), ty)
+	require.ErrorContains(t, err, `invalid key "$env", keys that start with $ must be written as "$$env"`)
+}
+
+func TestValueJSONUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+		"ips":  cty.Tuple([]cty.Type{cty.String}),
+	})
+
+	for desc, in := range map[string]string{
+		"unsupported attribute": `{"nope":"x"}`,
+		"invalid tuple length":  `{"ips":["a","b"]}`,
+		"invalid JSON":          `{"name":`,
+		"wrong marker value":    `{"name":{"$unknown":false}}`,
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := unmarshalValueJSON([]byte(in), ty)
+			require.Error(t, err)
+		})
+	}
+}
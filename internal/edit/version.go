@@ -0,0 +1,335 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+// planFormatVersion is the tfplan format version that every Terraform 1.x release writes. Terraform
+// only increments it for breaking changes, and refuses to read plans with any other version.
+const planFormatVersion = 3
+
+// minMinorVersion and maxMinorVersion are the Terraform 1.x minor versions whose plan formats are
+// known. The vendored plan.proto describes the latest of them.
+const (
+	minMinorVersion = 0
+	maxMinorVersion = 14
+)
+
+// fieldSince is the Terraform 1.x minor version that first wrote each field of the plan format.
+// Together with enumValueSince it defines the plan format of each Terraform version, see
+// planFormats: the fields and enum values of plan.proto that are missing here have been written
+// since 1.0. Fields are only ever added to the format, and field numbers are never reused.
+var fieldSince = map[protoreflect.FullName]int{
+	"plan.v1.Plan.relevant_attributes":                   2,
+	"plan.v1.Plan.check_results":                         3,
+	"plan.v1.Plan.errored":                               4,
+	"plan.v1.Plan.timestamp":                             5,
+	"plan.v1.Plan.provider_function_results":             8,
+	"plan.v1.Plan.applyable":                             8,
+	"plan.v1.Plan.complete":                              8,
+	"plan.v1.Plan.deferred_changes":                      9,
+	"plan.v1.Plan.apply_time_variables":                  10,
+	"plan.v1.Plan.action_invocations":                    14,
+	"plan.v1.Plan.deferred_action_invocations":           14,
+	"plan.v1.ResourceInstanceChange.prev_run_addr":       1,
+	"plan.v1.Change.importing":                           5,
+	"plan.v1.Change.generated_config":                    5,
+	"plan.v1.Change.before_identity":                     12,
+	"plan.v1.Change.after_identity":                      12,
+	"plan.v1.Importing.identity":                         12,
+	"plan.v1.DeferredResourceInstanceChange.deferred":    9,
+	"plan.v1.DeferredResourceInstanceChange.change":      9,
+	"plan.v1.ProviderFunctionCallHash.key":               8,
+	"plan.v1.ProviderFunctionCallHash.result":            8,
+	"plan.v1.CheckResults.ObjectResult.object_addr":      3,
+	"plan.v1.CheckResults.ObjectResult.status":           3,
+	"plan.v1.CheckResults.ObjectResult.failure_messages": 3,
+}
+
+// enumValueSince is the Terraform 1.x minor version that first wrote each enum value of the plan
+// format.
+var enumValueSince = map[protoreflect.FullName]int{
+	"plan.v1.FORGET":                            7,
+	"plan.v1.CREATE_THEN_FORGET":                9,
+	"plan.v1.DELETE_BECAUSE_NO_RESOURCE_CONFIG": 1,
+	"plan.v1.DELETE_BECAUSE_WRONG_REPETITION":   1,
+	"plan.v1.DELETE_BECAUSE_COUNT_INDEX":        1,
+	"plan.v1.DELETE_BECAUSE_EACH_KEY":           1,
+	"plan.v1.DELETE_BECAUSE_NO_MODULE":          1,
+	"plan.v1.REPLACE_BY_TRIGGERS":               2,
+	"plan.v1.READ_BECAUSE_CONFIG_UNKNOWN":       3,
+	"plan.v1.READ_BECAUSE_DEPENDENCY_PENDING":   3,
+	"plan.v1.READ_BECAUSE_CHECK_NESTED":         5,
+	"plan.v1.DELETE_BECAUSE_NO_MOVE_TARGET":     7,
+	"plan.v1.CheckResults.CHECK":                5,
+	"plan.v1.CheckResults.INPUT_VARIABLE":       9,
+}
+
+// planFormats are the plan formats of each Terraform 1.x minor version, indexed by the minor
+// version.
+var planFormats = func() []*protoregistry.Files {
+	formats := make([]*protoregistry.Files, maxMinorVersion+1)
+	for minor := minMinorVersion; minor <= maxMinorVersion; minor++ {
+		var err error
+		if formats[minor], err = newPlanFormat(minor); err != nil {
+			panic(fmt.Sprintf("invalid plan format for Terraform 1.%d: %s", minor, err))
+		}
+	}
+
+	return formats
+}()
+
+// newPlanFormat returns the plan format of the Terraform 1.x minor version, which is the vendored
+// plan.proto without the fields and enum values that the version doesn't write.
+func newPlanFormat(minor int) (*protoregistry.Files, error) {
+	fdp := protodesc.ToFileDescriptorProto(plan.File_proto_v1_plan_proto)
+	fdp.EnumType = pruneEnums(fdp.EnumType, fdp.GetPackage(), minor)
+	for _, m := range fdp.MessageType {
+		pruneMessage(m, fdp.GetPackage(), minor)
+	}
+
+	fd, err := protodesc.NewFile(fdp, &protoregistry.Files{})
+	if err != nil {
+		return nil, err
+	}
+
+	files := &protoregistry.Files{}
+
+	return files, files.RegisterFile(fd)
+}
+
+func pruneMessage(m *descriptorpb.DescriptorProto, scope string, minor int) {
+	name := scope + "." + m.GetName()
+	m.Field = slices.DeleteFunc(m.Field, func(f *descriptorpb.FieldDescriptorProto) bool {
+		return fieldSince[protoreflect.FullName(name+"."+f.GetName())] > minor
+	})
+	m.EnumType = pruneEnums(m.EnumType, name, minor)
+	for _, nested := range m.NestedType {
+		pruneMessage(nested, name, minor)
+	}
+}
+
+// pruneEnums removes the values that the minor version doesn't write from the enums. Enum values
+// are scoped to the enum's parent, as in C++.
+func pruneEnums(enums []*descriptorpb.EnumDescriptorProto, scope string, minor int) []*descriptorpb.EnumDescriptorProto {
+	for _, e := range enums {
+		e.Value = slices.DeleteFunc(e.Value, func(v *descriptorpb.EnumValueDescriptorProto) bool {
+			return enumValueSince[protoreflect.FullName(scope+"."+v.GetName())] > minor
+		})
+	}
+
+	return enums
+}
+
+// planVersion is the format version and Terraform version that a tfplan was written with.
+type planVersion struct {
+	format           uint64
+	terraformVersion string
+	minor            int
+	// schema is the plan format of the Terraform version.
+	schema *protoregistry.Files
+}
+
+func (v planVersion) String() string {
+	return "Terraform " + v.terraformVersion
+}
+
+// has returns whether the field or enum value is part of the version's plan format.
+func (v planVersion) has(name protoreflect.FullName) bool {
+	_, err := v.schema.FindDescriptorByName(name)
+	return err == nil
+}
+
+// unmarshalPlan decodes the tfplan after checking that it was written by a Terraform version whose
+// plan format is known. Fields and enum values that the Terraform version doesn't write, and
+// fields that aren't in the vendored schema, are reported as warnings.
+func unmarshalPlan(bytes []byte) (*plan.Plan, error) {
+	v, warnings, err := checkPlanVersion(bytes)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("tfplan: format version %d, %s\n", v.format, v)
+	for _, w := range warnings {
+		fmt.Printf("warning: tfplan: %s is not part of the %s plan format\n", w, v)
+	}
+
+	p := &plan.Plan{}
+	if err = proto.Unmarshal(bytes, p); err != nil {
+		return nil, err
+	}
+
+	for _, path := range collectUnknownFields(p).paths() {
+		fmt.Printf("warning: tfplan: %s has fields that aren't in the vendored plan schema, they'll be preserved as they are\n", path)
+	}
+
+	return p, nil
+}
+
+// marshalPlan encodes the tfplan and checks that the result is still a plan that its Terraform
+// version can read. Fields that aren't part of the version's plan format were already reported when
+// the plan was read, so they aren't reported again.
+func marshalPlan(p *plan.Plan) ([]byte, error) {
+	bytes, err := proto.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err = checkPlanVersion(bytes); err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// checkPlanVersion detects the format and Terraform versions of the serialized tfplan without
+// decoding it, then checks its encoding against the plan format of that Terraform version. It
+// returns an error for unknown versions and for fields whose encoding doesn't match the format,
+// either of which would otherwise be silently mangled when the plan is decoded. Fields that
+// aren't in the format at all are preserved as unknown fields, and the fields that the Terraform
+// version doesn't write are returned as warnings.
+func checkPlanVersion(bytes []byte) (planVersion, []string, error) {
+	v, err := detectPlanVersion(bytes)
+	if err != nil {
+		return v, nil, err
+	}
+
+	warnings, err := checkMessage(v, plan.File_proto_v1_plan_proto.Messages().ByName("Plan"), bytes)
+	if err != nil {
+		return v, nil, fmt.Errorf("tfplan does not match the %s plan format: %w", v, err)
+	}
+
+	return v, warnings, nil
+}
+
+// detectPlanVersion reads the version and terraform_version fields of the serialized tfplan.
+func detectPlanVersion(bytes []byte) (planVersion, error) {
+	v := planVersion{}
+	for len(bytes) > 0 {
+		num, typ, n := protowire.ConsumeTag(bytes)
+		if n < 0 {
+			return v, fmt.Errorf("unable to decode tfplan: %w", protowire.ParseError(n))
+		}
+		bytes = bytes[n:]
+
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v.format, n = protowire.ConsumeVarint(bytes)
+		case num == 14 && typ == protowire.BytesType:
+			var b []byte
+			b, n = protowire.ConsumeBytes(bytes)
+			v.terraformVersion = string(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, bytes)
+		}
+		if n < 0 {
+			return v, fmt.Errorf("unable to decode tfplan: %w", protowire.ParseError(n))
+		}
+		bytes = bytes[n:]
+	}
+
+	if v.format != planFormatVersion {
+		return v, fmt.Errorf("unsupported tfplan format version %d, only version %d written by Terraform 1.x is supported", v.format, planFormatVersion)
+	}
+
+	if v.terraformVersion == "" {
+		return v, errors.New("tfplan does not record the Terraform version that created it")
+	}
+
+	m := semverRe.FindStringSubmatch(v.terraformVersion)
+	if m == nil {
+		return v, fmt.Errorf("tfplan has an invalid Terraform version %q", v.terraformVersion)
+	}
+
+	minor, err := strconv.Atoi(m[2])
+	if err != nil || m[1] != "1" || minor < minMinorVersion || minor > maxMinorVersion {
+		return v, fmt.Errorf("unsupported Terraform version %s, only plans from Terraform 1.%d through 1.%d are supported", v.terraformVersion, minMinorVersion, maxMinorVersion)
+	}
+	v.minor = minor
+	v.schema = planFormats[minor]
+
+	return v, nil
+}
+
+// checkMessage checks every field of the serialized message against the plan format of the
+// version. It returns the names of the fields and enum values that the version doesn't write.
+func checkMessage(v planVersion, md protoreflect.MessageDescriptor, bytes []byte) ([]string, error) {
+	warnings := []string{}
+	for len(bytes) > 0 {
+		num, typ, n := protowire.ConsumeTag(bytes)
+		if n < 0 {
+			return nil, fmt.Errorf("%s: %w", md.FullName(), protowire.ParseError(n))
+		}
+		bytes = bytes[n:]
+
+		fd := md.Fields().ByNumber(num)
+		if fd == nil {
+			// Fields that aren't in the vendored schema are kept as they are.
+			n = protowire.ConsumeFieldValue(num, typ, bytes)
+			if n < 0 {
+				return nil, fmt.Errorf("%s: %w", md.FullName(), protowire.ParseError(n))
+			}
+			bytes = bytes[n:]
+			warnings = append(warnings, fmt.Sprintf("%s unknown field %d", md.FullName(), num))
+			continue
+		}
+
+		if want := wireType(fd); typ != want {
+			return nil, fmt.Errorf("%s is encoded with wire type %d, expected %d", fd.FullName(), typ, want)
+		}
+
+		if !v.has(fd.FullName()) {
+			warnings = append(warnings, string(fd.FullName()))
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, bytes)
+		if n < 0 {
+			return nil, fmt.Errorf("%s: %w", fd.FullName(), protowire.ParseError(n))
+		}
+		value := bytes[:n]
+		bytes = bytes[n:]
+
+		switch fd.Kind() {
+		case protoreflect.MessageKind:
+			b, _ := protowire.ConsumeBytes(value)
+			w, err := checkMessage(v, fd.Message(), b)
+			if err != nil {
+				return nil, err
+			}
+			warnings = append(warnings, w...)
+		case protoreflect.EnumKind:
+			i, _ := protowire.ConsumeVarint(value)
+			ev := fd.Enum().Values().ByNumber(protoreflect.EnumNumber(i))
+			switch {
+			case ev == nil:
+				warnings = append(warnings, fmt.Sprintf("%s value %d", fd.FullName(), i))
+			case !v.has(ev.FullName()):
+				warnings = append(warnings, fmt.Sprintf("%s value %s", fd.FullName(), ev.Name()))
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// wireType returns the wire type that the field is encoded with. The plan format has no
+// fixed-width or packed repeated scalar fields.
+func wireType(fd protoreflect.FieldDescriptor) protowire.Type {
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind:
+		return protowire.BytesType
+	default:
+		return protowire.VarintType
+	}
+}
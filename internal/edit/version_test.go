@@ -0,0 +1,190 @@
+package edit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	plan "github.com/ryancragun/terraform-plan-editor/internal/proto/v1"
+)
+
+func TestCheckPlanVersion(t *testing.T) {
+	t.Parallel()
+
+	for desc, test := range map[string]struct {
+		p     *plan.Plan
+		minor int
+		err   string
+	}{
+		"1.0": {
+			p:     &plan.Plan{Version: 3, TerraformVersion: "1.0.11"},
+			minor: 0,
+		},
+		"prerelease": {
+			p:     &plan.Plan{Version: 3, TerraformVersion: "1.9.0-beta1"},
+			minor: 9,
+		},
+		"no format version": {
+			p:   &plan.Plan{TerraformVersion: "1.9.1"},
+			err: "unsupported tfplan format version 0",
+		},
+		"old format version": {
+			p:   &plan.Plan{Version: 2, TerraformVersion: "0.11.14"},
+			err: "unsupported tfplan format version 2",
+		},
+		"no terraform version": {
+			p:   &plan.Plan{Version: 3},
+			err: "tfplan does not record the Terraform version that created it",
+		},
+		"invalid terraform version": {
+			p:   &plan.Plan{Version: 3, TerraformVersion: "v1.9.1"},
+			err: `tfplan has an invalid Terraform version "v1.9.1"`,
+		},
+		"0.15": {
+			p:   &plan.Plan{Version: 3, TerraformVersion: "0.15.5"},
+			err: "unsupported Terraform version 0.15.5, only plans from Terraform 1.0 through 1.14 are supported",
+		},
+		"newer minor": {
+			p:   &plan.Plan{Version: 3, TerraformVersion: "1.15.0"},
+			err: "unsupported Terraform version 1.15.0",
+		},
+		"newer major": {
+			p:   &plan.Plan{Version: 3, TerraformVersion: "2.0.0"},
+			err: "unsupported Terraform version 2.0.0",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := proto.Marshal(test.p)
+			require.NoError(t, err)
+
+			v, _, err := checkPlanVersion(b)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.minor, v.minor)
+		})
+	}
+}
+
+func TestCheckPlanVersionFormat(t *testing.T) {
+	t.Parallel()
+
+	p := &plan.Plan{
+		Version:          3,
+		TerraformVersion: "1.4.6",
+		ResourceChanges: []*plan.ResourceInstanceChange{
+			{
+				Addr: "aws_instance.web",
+				Change: &plan.Change{
+					Action:    plan.Action_FORGET,
+					Importing: &plan.Importing{Id: "i-1234"},
+				},
+				ActionReason: plan.ResourceInstanceActionReason_REPLACE_BY_TRIGGERS,
+			},
+		},
+	}
+	b, err := proto.Marshal(p)
+	require.NoError(t, err)
+
+	// Fields and enum values from newer versions are decoded, but reported.
+	v, err := detectPlanVersion(b)
+	require.NoError(t, err)
+	warnings, err := checkMessage(v, p.ProtoReflect().Descriptor(), b)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"plan.v1.Change.action value FORGET",
+		"plan.v1.Change.importing",
+	}, warnings)
+	_, planWarnings, err := checkPlanVersion(b)
+	require.NoError(t, err)
+	require.Equal(t, warnings, planWarnings)
+
+	// Fields that don't match the format are refused rather than mangled.
+	mismatch := protowire.AppendTag(b, 21, protowire.VarintType)
+	mismatch = protowire.AppendVarint(mismatch, 1)
+	_, _, err = checkPlanVersion(mismatch)
+	require.EqualError(t, err, "tfplan does not match the Terraform 1.4.6 plan format: plan.v1.Plan.timestamp is encoded with wire type 0, expected 2")
+
+	// Fields that aren't in the vendored schema are reported, but kept.
+	unknown := protowire.AppendTag(b, 99, protowire.BytesType)
+	unknown = protowire.AppendBytes(unknown, []byte("new"))
+	warnings, err = checkMessage(v, p.ProtoReflect().Descriptor(), unknown)
+	require.NoError(t, err)
+	require.Equal(t, "plan.v1.Plan unknown field 99", warnings[len(warnings)-1])
+}
+
+func TestEditTFPlanRefusesUnknownVersions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tfplan")
+	orig, err := proto.Marshal(&plan.Plan{Version: 3, TerraformVersion: "1.99.0"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, orig, 0o644))
+
+	err = editTFPlan(&Config{TextEditorCmd: "true", Format: FormatJSON}, path)
+	require.ErrorContains(t, err, "unsupported Terraform version 1.99.0")
+
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, orig, bytes)
+
+	// Edits can't change the version to one that isn't supported either.
+	orig, err = proto.Marshal(&plan.Plan{Version: 3, TerraformVersion: "1.9.1"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, orig, 0o644))
+	err = editTFPlan(&Config{TextEditorCmd: sedEditor(t, `s/1.9.1/1.99.0/`), Format: FormatJSON}, path)
+	require.ErrorContains(t, err, "unsupported Terraform version 1.99.0")
+}
+
+func TestPlanFormats(t *testing.T) {
+	t.Parallel()
+
+	// Every field and enum value whose version is recorded must be in the vendored schema.
+	latest := planVersion{schema: &protoregistry.Files{}}
+	require.NoError(t, latest.schema.RegisterFile(plan.File_proto_v1_plan_proto))
+	for name := range fieldSince {
+		require.True(t, latest.has(name), name)
+	}
+	for name := range enumValueSince {
+		require.True(t, latest.has(name), name)
+	}
+
+	for minor, test := range map[int]struct {
+		has     []protoreflect.FullName
+		missing []protoreflect.FullName
+	}{
+		0: {
+			has:     []protoreflect.FullName{"plan.v1.Plan.resource_changes", "plan.v1.DELETE", "plan.v1.Path.Step.element_key"},
+			missing: []protoreflect.FullName{"plan.v1.Plan.relevant_attributes", "plan.v1.ResourceInstanceChange.prev_run_addr", "plan.v1.REPLACE_BY_TRIGGERS"},
+		},
+		5: {
+			has:     []protoreflect.FullName{"plan.v1.Change.importing", "plan.v1.CheckResults.CHECK", "plan.v1.READ_BECAUSE_CHECK_NESTED"},
+			missing: []protoreflect.FullName{"plan.v1.Plan.complete", "plan.v1.FORGET", "plan.v1.CheckResults.INPUT_VARIABLE"},
+		},
+		8: {
+			has:     []protoreflect.FullName{"plan.v1.Plan.provider_function_results", "plan.v1.FORGET"},
+			missing: []protoreflect.FullName{"plan.v1.Plan.deferred_changes", "plan.v1.CREATE_THEN_FORGET", "plan.v1.Importing.identity"},
+		},
+		maxMinorVersion: {
+			has: []protoreflect.FullName{"plan.v1.Plan.action_invocations", "plan.v1.Importing.identity"},
+		},
+	} {
+		v := planVersion{minor: minor, schema: planFormats[minor]}
+		for _, name := range test.has {
+			require.True(t, v.has(name), "1.%d: %s", minor, name)
+		}
+		for _, name := range test.missing {
+			require.False(t, v.has(name), "1.%d: %s", minor, name)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Package mapkeys returns the keys of maps in a deterministic order.
+package mapkeys
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Sorted returns the keys of the map in order.
+func Sorted[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
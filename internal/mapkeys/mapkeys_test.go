@@ -0,0 +1,15 @@
+package mapkeys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSorted(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a", "b", "c"}, Sorted(map[string]bool{"c": true, "a": false, "b": true}))
+	require.Equal(t, []int{1, 2, 10}, Sorted(map[int]string{10: "x", 2: "y", 1: "z"}))
+	require.Empty(t, Sorted(map[string]int{}))
+}
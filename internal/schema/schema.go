@@ -0,0 +1,216 @@
+// Package schema loads the provider schemas written by `terraform providers schema -json` and
+// derives the cty types of the values that Terraform encodes with them.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+)
+
+// Nesting modes of nested blocks and nested attribute types.
+const (
+	NestingSingle = "single"
+	NestingGroup  = "group"
+	NestingList   = "list"
+	NestingSet    = "set"
+	NestingMap    = "map"
+)
+
+// Schemas are the provider schemas written by `terraform providers schema -json`. Terraform does
+// not include backend schemas in that output, so they can be added to the same file under
+// "backend_schemas", keyed by backend type, using the same format as a resource schema's block.
+type Schemas struct {
+	FormatVersion   string                     `json:"format_version"`
+	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
+	BackendSchemas  map[string]*Block          `json:"backend_schemas"`
+}
+
+// ProviderSchema is the schema of a provider and all of its resources and data sources.
+type ProviderSchema struct {
+	Provider          *Schema            `json:"provider"`
+	ResourceSchemas   map[string]*Schema `json:"resource_schemas"`
+	DataSourceSchemas map[string]*Schema `json:"data_source_schemas"`
+}
+
+// Schema is a versioned block schema.
+type Schema struct {
+	Version int64  `json:"version"`
+	Block   *Block `json:"block"`
+}
+
+// Block is the schema of a configuration block.
+type Block struct {
+	Attributes map[string]*Attribute   `json:"attributes"`
+	BlockTypes map[string]*NestedBlock `json:"block_types"`
+}
+
+// Attribute is the schema of an attribute. An attribute either has a Type or a NestedType.
+type Attribute struct {
+	Type       cty.Type `json:"type"`
+	NestedType *Object  `json:"nested_type"`
+	Required   bool     `json:"required"`
+	Optional   bool     `json:"optional"`
+	Computed   bool     `json:"computed"`
+	Sensitive  bool     `json:"sensitive"`
+}
+
+// Object is the schema of a nested attribute type.
+type Object struct {
+	Attributes  map[string]*Attribute `json:"attributes"`
+	NestingMode string                `json:"nesting_mode"`
+}
+
+// NestedBlock is the schema of a nested block type.
+type NestedBlock struct {
+	NestingMode string `json:"nesting_mode"`
+	Block       *Block `json:"block"`
+	MinItems    int    `json:"min_items"`
+	MaxItems    int    `json:"max_items"`
+}
+
+// Load reads the schemas from a file.
+func Load(path string) (*Schemas, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read provider schemas: %w", err)
+	}
+
+	return Parse(bytes)
+}
+
+// Parse parses the JSON output of `terraform providers schema -json`.
+func Parse(bytes []byte) (*Schemas, error) {
+	s := &Schemas{}
+	if err := json.Unmarshal(bytes, s); err != nil {
+		return nil, fmt.Errorf("unable to parse provider schemas: %w", err)
+	}
+
+	if s.FormatVersion == "" {
+		return nil, fmt.Errorf("unable to parse provider schemas: missing format_version, expected the output of `terraform providers schema -json`")
+	}
+	if major, _, _ := strings.Cut(s.FormatVersion, "."); major != "1" {
+		return nil, fmt.Errorf("unable to parse provider schemas: unsupported format_version %q", s.FormatVersion)
+	}
+
+	return s, nil
+}
+
+// Provider returns the schema of the provider.
+func (s *Schemas) Provider(provider addrs.Provider) (*ProviderSchema, error) {
+	for source, ps := range s.ProviderSchemas {
+		p, err := addrs.ParseProvider(source)
+		if err == nil && p == provider {
+			return ps, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no schema for provider %s", provider)
+}
+
+// Resource returns the schema of a resource or data source type.
+func (s *Schemas) Resource(provider addrs.Provider, mode addrs.ResourceMode, typ string) (*Schema, error) {
+	ps, err := s.Provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, kind := ps.ResourceSchemas, "resource"
+	if mode == addrs.DataResourceMode {
+		schemas, kind = ps.DataSourceSchemas, "data source"
+	}
+
+	schema, ok := schemas[typ]
+	if !ok || schema.Block == nil {
+		return nil, fmt.Errorf("no schema for %s type %s in provider %s", kind, typ, provider)
+	}
+
+	return schema, nil
+}
+
+// Backend returns the schema of a backend type.
+func (s *Schemas) Backend(typ string) (*Block, error) {
+	b, ok := s.BackendSchemas[typ]
+	if !ok || b == nil {
+		return nil, fmt.Errorf("no schema for backend %s", typ)
+	}
+
+	return b, nil
+}
+
+// ImpliedType returns the type of the object values that conform to the block, which is the type
+// Terraform uses to encode them.
+func (b *Block) ImpliedType() cty.Type {
+	if b == nil {
+		return cty.EmptyObject
+	}
+
+	attrs := map[string]cty.Type{}
+	for name, a := range b.Attributes {
+		attrs[name] = a.ImpliedType()
+	}
+
+	for name, nb := range b.BlockTypes {
+		ety := nb.Block.ImpliedType()
+		switch nb.NestingMode {
+		case NestingList:
+			// A list of blocks that contain dynamically typed attributes may contain blocks of
+			// different types, so Terraform encodes it as a tuple.
+			if ety.HasDynamicTypes() {
+				attrs[name] = cty.DynamicPseudoType
+			} else {
+				attrs[name] = cty.List(ety)
+			}
+		case NestingSet:
+			attrs[name] = cty.Set(ety)
+		case NestingMap:
+			if ety.HasDynamicTypes() {
+				attrs[name] = cty.DynamicPseudoType
+			} else {
+				attrs[name] = cty.Map(ety)
+			}
+		default:
+			attrs[name] = ety
+		}
+	}
+
+	return cty.Object(attrs)
+}
+
+// ImpliedType returns the type of the attribute's values.
+func (a *Attribute) ImpliedType() cty.Type {
+	if a.NestedType != nil {
+		return a.NestedType.ImpliedType()
+	}
+
+	if a.Type == cty.NilType {
+		return cty.DynamicPseudoType
+	}
+
+	return a.Type
+}
+
+// ImpliedType returns the type of the nested attribute's values.
+func (o *Object) ImpliedType() cty.Type {
+	attrs := map[string]cty.Type{}
+	for name, a := range o.Attributes {
+		attrs[name] = a.ImpliedType()
+	}
+	ety := cty.Object(attrs)
+
+	switch o.NestingMode {
+	case NestingList:
+		return cty.List(ety)
+	case NestingSet:
+		return cty.Set(ety)
+	case NestingMap:
+		return cty.Map(ety)
+	default:
+		return ety
+	}
+}
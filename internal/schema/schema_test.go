@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+)
+
+const testSchemasJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "provider": {"version": 0, "block": {"attributes": {"region": {"type": "string", "optional": true}}}},
+      "resource_schemas": {
+        "aws_instance": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true},
+              "ami": {"type": "string", "required": true},
+              "tags": {"type": ["map", "string"], "optional": true},
+              "security_groups": {"type": ["set", "string"], "optional": true},
+              "password": {"type": "string", "optional": true, "sensitive": true},
+              "metadata": {"type": "dynamic", "optional": true},
+              "network": {
+                "nested_type": {
+                  "nesting_mode": "list",
+                  "attributes": {"subnet": {"type": "string", "required": true}}
+                },
+                "optional": true
+              }
+            },
+            "block_types": {
+              "root_block_device": {
+                "nesting_mode": "list",
+                "max_items": 1,
+                "block": {"attributes": {"size": {"type": "number", "optional": true}}}
+              },
+              "ebs_block_device": {
+                "nesting_mode": "set",
+                "block": {"attributes": {"device_name": {"type": "string", "required": true}}}
+              },
+              "timeouts": {
+                "nesting_mode": "single",
+                "block": {"attributes": {"create": {"type": "string", "optional": true}}}
+              },
+              "dynamic_list": {
+                "nesting_mode": "list",
+                "block": {"attributes": {"value": {"type": "dynamic", "optional": true}}}
+              },
+              "labels": {
+                "nesting_mode": "map",
+                "block": {"attributes": {"value": {"type": "string", "optional": true}}}
+              }
+            }
+          }
+        }
+      },
+      "data_source_schemas": {
+        "aws_ami": {"version": 0, "block": {"attributes": {"id": {"type": "string", "computed": true}}}}
+      }
+    }
+  },
+  "backend_schemas": {
+    "s3": {"attributes": {"bucket": {"type": "string", "required": true}}}
+  }
+}`
+
+func testSchemas(t *testing.T) *Schemas {
+	t.Helper()
+
+	s, err := Parse([]byte(testSchemasJSON))
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for input, err := range map[string]string{
+		`{`:                         "unable to parse provider schemas",
+		`{"provider_schemas": {}}`:  "missing format_version",
+		`{"format_version": "2.0"}`: `unsupported format_version "2.0"`,
+		`{"format_version": "1.0", "provider_schemas": {"aws": {"resource_schemas": {"x": {"block": {"attributes": {"a": {"type": "strung"}}}}}}}}`: "unable to parse provider schemas",
+	} {
+		_, e := Parse([]byte(input))
+		require.ErrorContains(t, e, err, input)
+	}
+}
+
+func TestResource(t *testing.T) {
+	t.Parallel()
+
+	s := testSchemas(t)
+
+	aws, err := addrs.ParseProvider("hashicorp/aws")
+	require.NoError(t, err)
+
+	r, err := s.Resource(aws, addrs.ManagedResourceMode, "aws_instance")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), r.Version)
+
+	_, err = s.Resource(aws, addrs.DataResourceMode, "aws_ami")
+	require.NoError(t, err)
+
+	_, err = s.Resource(aws, addrs.DataResourceMode, "aws_instance")
+	require.ErrorContains(t, err, "no schema for data source type aws_instance in provider registry.terraform.io/hashicorp/aws")
+
+	google, err := addrs.ParseProvider("hashicorp/google")
+	require.NoError(t, err)
+	_, err = s.Resource(google, addrs.ManagedResourceMode, "google_compute_instance")
+	require.ErrorContains(t, err, "no schema for provider registry.terraform.io/hashicorp/google")
+
+	_, err = s.Backend("s3")
+	require.NoError(t, err)
+	_, err = s.Backend("gcs")
+	require.ErrorContains(t, err, "no schema for backend gcs")
+}
+
+func TestImpliedType(t *testing.T) {
+	t.Parallel()
+
+	aws, err := addrs.ParseProvider("hashicorp/aws")
+	require.NoError(t, err)
+	r, err := testSchemas(t).Resource(aws, addrs.ManagedResourceMode, "aws_instance")
+	require.NoError(t, err)
+
+	require.True(t, cty.Object(map[string]cty.Type{
+		"id":              cty.String,
+		"ami":             cty.String,
+		"tags":            cty.Map(cty.String),
+		"security_groups": cty.Set(cty.String),
+		"password":        cty.String,
+		"metadata":        cty.DynamicPseudoType,
+		"network":         cty.List(cty.Object(map[string]cty.Type{"subnet": cty.String})),
+		"root_block_device": cty.List(cty.Object(map[string]cty.Type{
+			"size": cty.Number,
+		})),
+		"ebs_block_device": cty.Set(cty.Object(map[string]cty.Type{
+			"device_name": cty.String,
+		})),
+		"timeouts":     cty.Object(map[string]cty.Type{"create": cty.String}),
+		"dynamic_list": cty.DynamicPseudoType,
+		"labels":       cty.Map(cty.Object(map[string]cty.Type{"value": cty.String})),
+	}).Equals(r.Block.ImpliedType()), r.Block.ImpliedType().GoString())
+
+	var nilBlock *Block
+	require.True(t, cty.EmptyObject.Equals(nilBlock.ImpliedType()))
+}
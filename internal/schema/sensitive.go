@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+)
+
+// SensitivePaths returns the path of every attribute in the value that the block declares as
+// sensitive, including those in nested blocks and nested attribute types. Elements of lists and
+// maps are addressed individually. Set elements can't be addressed by a path, so if any
+// attribute within a set is sensitive the whole set is. The value must have the block's implied
+// type.
+func (b *Block) SensitivePaths(val cty.Value) []cty.Path {
+	return b.sensitivePaths(cty.Path{}, val)
+}
+
+func (b *Block) sensitivePaths(path cty.Path, val cty.Value) []cty.Path {
+	if b == nil || val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() {
+		return nil
+	}
+
+	paths := attributesSensitivePaths(b.Attributes, path, val)
+
+	for _, name := range mapkeys.Sorted(b.BlockTypes) {
+		if !val.Type().HasAttribute(name) {
+			continue
+		}
+
+		nb, v, p := b.BlockTypes[name], val.GetAttr(name), path.GetAttr(name)
+		switch {
+		case nb.NestingMode == NestingSet:
+			if nb.Block.hasSensitive() {
+				paths = append(paths, p)
+			}
+		case nb.NestingMode == NestingList || nb.NestingMode == NestingMap:
+			paths = append(paths, elementsSensitivePaths(p, v, nb.Block.sensitivePaths)...)
+		default:
+			paths = append(paths, nb.Block.sensitivePaths(p, v)...)
+		}
+	}
+
+	return paths
+}
+
+func (o *Object) sensitivePaths(path cty.Path, val cty.Value) []cty.Path {
+	switch o.NestingMode {
+	case NestingSet:
+		for _, a := range o.Attributes {
+			if a.hasSensitive() {
+				return []cty.Path{path}
+			}
+		}
+		return nil
+	case NestingList, NestingMap:
+		return elementsSensitivePaths(path, val, func(p cty.Path, v cty.Value) []cty.Path {
+			return attributesSensitivePaths(o.Attributes, p, v)
+		})
+	default:
+		return attributesSensitivePaths(o.Attributes, path, val)
+	}
+}
+
+func attributesSensitivePaths(attrs map[string]*Attribute, path cty.Path, val cty.Value) []cty.Path {
+	if val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() {
+		return nil
+	}
+
+	paths := []cty.Path{}
+	for _, name := range mapkeys.Sorted(attrs) {
+		if !val.Type().HasAttribute(name) {
+			continue
+		}
+
+		a, p := attrs[name], path.GetAttr(name)
+		switch {
+		case a.Sensitive:
+			paths = append(paths, p)
+		case a.NestedType != nil:
+			paths = append(paths, a.NestedType.sensitivePaths(p, val.GetAttr(name))...)
+		}
+	}
+
+	return paths
+}
+
+func elementsSensitivePaths(path cty.Path, val cty.Value, elementPaths func(cty.Path, cty.Value) []cty.Path) []cty.Path {
+	if val.IsNull() || !val.IsKnown() || !val.CanIterateElements() {
+		return nil
+	}
+
+	paths := []cty.Path{}
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		paths = append(paths, elementPaths(path.Index(k), v)...)
+	}
+
+	return paths
+}
+
+func (b *Block) hasSensitive() bool {
+	if b == nil {
+		return false
+	}
+
+	for _, a := range b.Attributes {
+		if a.hasSensitive() {
+			return true
+		}
+	}
+
+	for _, nb := range b.BlockTypes {
+		if nb.Block.hasSensitive() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *Attribute) hasSensitive() bool {
+	if a.Sensitive {
+		return true
+	}
+
+	if a.NestedType == nil {
+		return false
+	}
+
+	for _, na := range a.NestedType.Attributes {
+		if na.hasSensitive() {
+			return true
+		}
+	}
+
+	return false
+}
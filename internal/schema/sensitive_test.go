@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testSensitiveBlockJSON = `{
+  "attributes": {
+    "name": {"type": "string", "optional": true},
+    "password": {"type": "string", "optional": true, "sensitive": true},
+    "users": {
+      "nested_type": {
+        "nesting_mode": "map",
+        "attributes": {"token": {"type": "string", "optional": true, "sensitive": true}}
+      },
+      "optional": true
+    }
+  },
+  "block_types": {
+    "disk": {
+      "nesting_mode": "list",
+      "block": {"attributes": {"size": {"type": "number", "optional": true}, "key": {"type": "string", "optional": true, "sensitive": true}}}
+    },
+    "secret": {
+      "nesting_mode": "set",
+      "block": {"attributes": {"value": {"type": "string", "optional": true, "sensitive": true}}}
+    },
+    "tag": {
+      "nesting_mode": "set",
+      "block": {"attributes": {"value": {"type": "string", "optional": true}}}
+    }
+  }
+}`
+
+func TestSensitivePaths(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(`{"format_version": "1.0", "backend_schemas": {"test": ` + testSensitiveBlockJSON + `}}`))
+	require.NoError(t, err)
+	block, err := s.Backend("test")
+	require.NoError(t, err)
+	ty := block.ImpliedType()
+
+	require.Empty(t, block.SensitivePaths(cty.NullVal(ty)))
+	require.Empty(t, block.SensitivePaths(cty.UnknownVal(ty)))
+
+	disk := func(size int64) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(size), "key": cty.StringVal("k")})
+	}
+	val := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("web"),
+		"password": cty.StringVal("hunter2"),
+		"users": cty.MapVal(map[string]cty.Value{
+			"admin": cty.ObjectVal(map[string]cty.Value{"token": cty.StringVal("t")}),
+		}),
+		"disk":   cty.ListVal([]cty.Value{disk(8), disk(16)}),
+		"secret": cty.SetVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"value": cty.StringVal("s")})}),
+		"tag":    cty.SetVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"value": cty.StringVal("t")})}),
+	})
+
+	paths := []string{}
+	for _, p := range block.SensitivePaths(val) {
+		paths = append(paths, formatPath(p))
+	}
+	require.Equal(t, []string{
+		".password",
+		`.users["admin"].token`,
+		".disk[0].key",
+		".disk[1].key",
+		".secret",
+	}, paths)
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/mapkeys"
+)
+
+// Validate returns an error for each way the value doesn't conform to the block: required
+// attributes that are null and nested blocks that violate their item limits. The value must
+// already have the block's implied type. The errors are cty.PathErrors that locate each problem
+// within the value. Unknown values are assumed to be valid.
+func (b *Block) Validate(val cty.Value) []error {
+	return b.validate(cty.Path{}, val)
+}
+
+func (b *Block) validate(path cty.Path, val cty.Value) []error {
+	if b == nil || val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() {
+		return nil
+	}
+
+	errs := validateAttributes(b.Attributes, path, val)
+
+	for _, name := range mapkeys.Sorted(b.BlockTypes) {
+		if !val.Type().HasAttribute(name) {
+			continue
+		}
+		errs = append(errs, b.BlockTypes[name].validate(path.GetAttr(name), val.GetAttr(name))...)
+	}
+
+	return errs
+}
+
+func (nb *NestedBlock) validate(path cty.Path, val cty.Value) []error {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	switch nb.NestingMode {
+	case NestingList, NestingSet, NestingMap:
+	default:
+		return nb.Block.validate(path, val)
+	}
+
+	if !val.CanIterateElements() {
+		return nil
+	}
+
+	errs := []error{}
+	if n := val.LengthInt(); nb.MinItems > 0 && n < nb.MinItems {
+		errs = append(errs, path.NewErrorf("at least %d blocks are required, found %d", nb.MinItems, n))
+	} else if nb.MaxItems > 0 && n > nb.MaxItems {
+		errs = append(errs, path.NewErrorf("at most %d blocks are allowed, found %d", nb.MaxItems, n))
+	}
+
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		errs = append(errs, nb.Block.validate(path.Index(k), v)...)
+	}
+
+	return errs
+}
+
+func (o *Object) validate(path cty.Path, val cty.Value) []error {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	if o.NestingMode == NestingSingle || o.NestingMode == NestingGroup || !val.CanIterateElements() {
+		return validateAttributes(o.Attributes, path, val)
+	}
+
+	errs := []error{}
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		errs = append(errs, validateAttributes(o.Attributes, path.Index(k), v)...)
+	}
+
+	return errs
+}
+
+func validateAttributes(attrs map[string]*Attribute, path cty.Path, val cty.Value) []error {
+	if val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() {
+		return nil
+	}
+
+	errs := []error{}
+	for _, name := range mapkeys.Sorted(attrs) {
+		if !val.Type().HasAttribute(name) {
+			continue
+		}
+
+		a, v, p := attrs[name], val.GetAttr(name), path.GetAttr(name)
+		if a.Required && v.IsNull() {
+			errs = append(errs, p.NewErrorf("required attribute is null"))
+		}
+		if a.NestedType != nil {
+			errs = append(errs, a.NestedType.validate(p, v)...)
+		}
+	}
+
+	return errs
+}
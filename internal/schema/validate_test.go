@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/ryancragun/terraform-plan-editor/internal/addrs"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	aws, err := addrs.ParseProvider("hashicorp/aws")
+	require.NoError(t, err)
+	r, err := testSchemas(t).Resource(aws, addrs.ManagedResourceMode, "aws_instance")
+	require.NoError(t, err)
+	block := r.Block
+	ty := block.ImpliedType()
+
+	valid := func(overrides map[string]cty.Value) cty.Value {
+		attrs := map[string]cty.Value{}
+		for name, aty := range ty.AttributeTypes() {
+			attrs[name] = cty.NullVal(aty)
+		}
+		attrs["ami"] = cty.StringVal("ami-1234")
+		attrs["root_block_device"] = cty.ListValEmpty(ty.AttributeType("root_block_device").ElementType())
+		attrs["ebs_block_device"] = cty.SetValEmpty(ty.AttributeType("ebs_block_device").ElementType())
+		attrs["labels"] = cty.MapValEmpty(ty.AttributeType("labels").ElementType())
+		attrs["dynamic_list"] = cty.EmptyTupleVal
+		for name, v := range overrides {
+			attrs[name] = v
+		}
+
+		return cty.ObjectVal(attrs)
+	}
+
+	require.Empty(t, block.Validate(valid(nil)))
+	require.Empty(t, block.Validate(valid(map[string]cty.Value{"ami": cty.UnknownVal(cty.String)})))
+	require.Empty(t, block.Validate(cty.NullVal(ty)))
+	require.Empty(t, block.Validate(cty.UnknownVal(ty)))
+
+	device := func(size cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"size": size})
+	}
+	ebs := func(name cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"device_name": name})
+	}
+
+	errs := block.Validate(valid(map[string]cty.Value{
+		"ami":               cty.NullVal(cty.String),
+		"root_block_device": cty.ListVal([]cty.Value{device(cty.NumberIntVal(8)), device(cty.NumberIntVal(16))}),
+		"ebs_block_device":  cty.SetVal([]cty.Value{ebs(cty.NullVal(cty.String))}),
+		"network": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"subnet": cty.StringVal("a")}),
+			cty.ObjectVal(map[string]cty.Value{"subnet": cty.NullVal(cty.String)}),
+		}),
+	}))
+
+	msgs := []string{}
+	for _, err := range errs {
+		var pathErr cty.PathError
+		require.True(t, errors.As(err, &pathErr))
+		msgs = append(msgs, formatPath(pathErr.Path)+": "+err.Error())
+	}
+	require.Equal(t, []string{
+		".ami: required attribute is null",
+		".network[1].subnet: required attribute is null",
+		".ebs_block_device[?].device_name: required attribute is null",
+		".root_block_device: at most 1 blocks are allowed, found 2",
+	}, msgs)
+}
+
+func formatPath(path cty.Path) string {
+	s := ""
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			s += "." + step.Name
+		case cty.IndexStep:
+			switch step.Key.Type() {
+			case cty.Number:
+				s += "[" + step.Key.AsBigFloat().String() + "]"
+			case cty.String:
+				s += "[" + strconv.Quote(step.Key.AsString()) + "]"
+			default:
+				s += "[?]"
+			}
+		}
+	}
+
+	return s
+}
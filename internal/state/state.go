@@ -0,0 +1,202 @@
+// Package state reads and writes Terraform state snapshots in the version 4 format that Terraform
+// 0.12 and later use for terraform.tfstate and for the tfstate and tfstate-prev members of a plan.
+package state
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Version is the only state format version that is supported.
+const Version = 4
+
+// State is a state snapshot. The fields are in the same order as Terraform writes them.
+type State struct {
+	Version          uint64             `json:"version"`
+	TerraformVersion string             `json:"terraform_version"`
+	Serial           uint64             `json:"serial"`
+	Lineage          string             `json:"lineage"`
+	Outputs          map[string]*Output `json:"outputs"`
+	Resources        []*Resource        `json:"resources"`
+	CheckResults     json.RawMessage    `json:"check_results"`
+}
+
+// Output is the value of a root module output.
+type Output struct {
+	Value     json.RawMessage `json:"value"`
+	Type      json.RawMessage `json:"type"`
+	Sensitive bool            `json:"sensitive,omitempty"`
+}
+
+// Resource is a resource and all of its instances. Module is the module instance address, which is
+// empty for resources in the root module, and Provider is the provider configuration address.
+type Resource struct {
+	Module    string      `json:"module,omitempty"`
+	Mode      string      `json:"mode"`
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Each      string      `json:"each,omitempty"`
+	Provider  string      `json:"provider"`
+	Instances []*Instance `json:"instances"`
+}
+
+// Instance is a resource instance object. Deposed is set for deposed objects.
+type Instance struct {
+	IndexKey              any               `json:"index_key,omitempty"`
+	Status                string            `json:"status,omitempty"`
+	Deposed               string            `json:"deposed,omitempty"`
+	SchemaVersion         uint64            `json:"schema_version"`
+	Attributes            json.RawMessage   `json:"attributes,omitempty"`
+	AttributesFlat        map[string]string `json:"attributes_flat,omitempty"`
+	SensitiveAttributes   json.RawMessage   `json:"sensitive_attributes,omitempty"`
+	IdentitySchemaVersion *uint64           `json:"identity_schema_version,omitempty"`
+	Identity              json.RawMessage   `json:"identity,omitempty"`
+	Private               []byte            `json:"private,omitempty"`
+	Dependencies          []string          `json:"dependencies,omitempty"`
+	CreateBeforeDestroy   bool              `json:"create_before_destroy,omitempty"`
+}
+
+// Load reads a state snapshot from a file.
+func Load(path string) (*State, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Parse(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse state %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Parse decodes a state snapshot. Index keys are kept exactly as they were written.
+func Parse(b []byte) (*State, error) {
+	version := struct {
+		Version *uint64 `json:"version"`
+	}{}
+	if err := json.Unmarshal(b, &version); err != nil {
+		return nil, err
+	}
+	if version.Version == nil {
+		return nil, errors.New("state does not record its format version")
+	}
+	if *version.Version != Version {
+		return nil, fmt.Errorf("unsupported state format version %d, only version %d is supported", *version.Version, Version)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	s := &State{}
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	if s.Outputs == nil {
+		s.Outputs = map[string]*Output{}
+	}
+
+	return s, nil
+}
+
+// Marshal encodes the state snapshot with the same indentation as Terraform.
+func (s *State) Marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// NewLineage returns a new random lineage.
+func NewLineage() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// pathStep is a step of a path in sensitive_attributes.
+type pathStep struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalPaths encodes paths in the format of sensitive_attributes.
+func MarshalPaths(paths []cty.Path) (json.RawMessage, error) {
+	steps := [][]pathStep{}
+	for _, path := range paths {
+		ps := []pathStep{}
+		for _, step := range path {
+			switch s := step.(type) {
+			case cty.GetAttrStep:
+				name, err := json.Marshal(s.Name)
+				if err != nil {
+					return nil, err
+				}
+				ps = append(ps, pathStep{Type: "get_attr", Value: name})
+			case cty.IndexStep:
+				key, err := ctyjson.Marshal(s.Key, cty.DynamicPseudoType)
+				if err != nil {
+					return nil, err
+				}
+				ps = append(ps, pathStep{Type: "index", Value: key})
+			default:
+				return nil, fmt.Errorf("unsupported path step %T", step)
+			}
+		}
+		steps = append(steps, ps)
+	}
+
+	return json.Marshal(steps)
+}
+
+// UnmarshalPaths decodes paths in the format of sensitive_attributes.
+func UnmarshalPaths(raw json.RawMessage) ([]cty.Path, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	steps := [][]pathStep{}
+	if err := json.Unmarshal(raw, &steps); err != nil {
+		return nil, fmt.Errorf("invalid sensitive attributes: %w", err)
+	}
+
+	paths := []cty.Path{}
+	for _, ps := range steps {
+		path := cty.Path{}
+		for _, step := range ps {
+			switch step.Type {
+			case "get_attr":
+				var name string
+				if err := json.Unmarshal(step.Value, &name); err != nil {
+					return nil, fmt.Errorf("invalid sensitive attributes: invalid attribute name: %w", err)
+				}
+				path = path.GetAttr(name)
+			case "index":
+				key, err := ctyjson.Unmarshal(step.Value, cty.DynamicPseudoType)
+				if err != nil {
+					return nil, fmt.Errorf("invalid sensitive attributes: invalid index: %w", err)
+				}
+				path = path.Index(key)
+			default:
+				return nil, fmt.Errorf("invalid sensitive attributes: unsupported path step type %q", step.Type)
+			}
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
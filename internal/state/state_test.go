@@ -0,0 +1,106 @@
+package state
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testState = `{
+  "version": 4,
+  "terraform_version": "1.9.1",
+  "serial": 3,
+  "lineage": "0b7b5e8c-4b8f-4b64-9a5e-3f0d2b8e8b1a",
+  "outputs": {
+    "ami": {
+      "value": "ami-web",
+      "type": "string"
+    }
+  },
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "index_key": 0,
+          "schema_version": 1,
+          "attributes": {
+            "ami": "ami-web",
+            "size": 10000000000000000001
+          },
+          "sensitive_attributes": [],
+          "dependencies": [
+            "aws_vpc.main"
+          ]
+        }
+      ]
+    }
+  ],
+  "check_results": null
+}
+`
+
+func TestParseMarshal(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testState))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, s.Serial)
+	require.Len(t, s.Resources[0].Instances, 1)
+
+	// States are written back exactly as Terraform writes them.
+	b, err := s.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, testState, string(b))
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse([]byte(`{"version": 3, "serial": 1}`))
+	require.EqualError(t, err, "unsupported state format version 3, only version 4 is supported")
+
+	_, err = Parse([]byte(`{"serial": 1}`))
+	require.EqualError(t, err, "state does not record its format version")
+}
+
+func TestPaths(t *testing.T) {
+	t.Parallel()
+
+	paths := []cty.Path{
+		cty.GetAttrPath("password"),
+		cty.GetAttrPath("tags").Index(cty.StringVal("secret")),
+		cty.GetAttrPath("disks").Index(cty.NumberIntVal(0)).GetAttr("key"),
+	}
+
+	raw, err := MarshalPaths(paths)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		[{"type": "get_attr", "value": "password"}],
+		[{"type": "get_attr", "value": "tags"}, {"type": "index", "value": {"value": "secret", "type": "string"}}],
+		[{"type": "get_attr", "value": "disks"}, {"type": "index", "value": {"value": 0, "type": "number"}}, {"type": "get_attr", "value": "key"}]
+	]`, string(raw))
+
+	decoded, err := UnmarshalPaths(raw)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(paths))
+	for i := range paths {
+		require.True(t, paths[i].Equals(decoded[i]), "path %d", i)
+	}
+
+	_, err = UnmarshalPaths([]byte(`[[{"type": "splat"}]]`))
+	require.EqualError(t, err, `invalid sensitive attributes: unsupported path step type "splat"`)
+}
+
+func TestNewLineage(t *testing.T) {
+	t.Parallel()
+
+	lineage, err := NewLineage()
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), lineage)
+}
@@ -7,13 +7,20 @@ import (
 	"path/filepath"
 
 	"github.com/ryancragun/terraform-plan-editor/internal/edit"
+	"github.com/ryancragun/terraform-plan-editor/internal/schema"
 )
 
-var config = &edit.Config{}
+var (
+	config     = &edit.Config{}
+	schemaPath string
+)
 
 func init() {
 	flag.StringVar(&config.TextEditorCmd, "editor", "", "the editor to use when editing text files")
-	flag.StringVar(&config.BinEditorCmd, "bin-editor", "", "the editor to use when editing binary files")
+	flag.StringVar(&config.Format, "format", edit.FormatJSON, "the format of editing documents, either json or yaml")
+	flag.BoolVar(&config.SingleDocument, "single-document", false, "edit all dynamic values in a single document")
+	flag.StringVar(&config.BinEditorCmd, "bin-editor", "", "the editor to use when editing binary files, if unset they are edited as a hex dump with the text editor")
+	flag.StringVar(&schemaPath, "schema", "", "the output of 'terraform providers schema -json', used to decode resource and backend values with their exact types")
 }
 
 func getEditorCmd(cmd string) (string, error) {
@@ -30,21 +37,24 @@ func getEditorCmd(cmd string) (string, error) {
 	return "", fmt.Errorf("you must set the editor with the '-editor' flag or set $EDITOR")
 }
 
-func getBinEditorCmd(cmd string) (string, error) {
+func getBinEditorCmd(cmd string) string {
 	if cmd != "" {
 		fmt.Println("binary editor: " + cmd)
-		return cmd, nil
-	}
-
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		fmt.Println("binary editor: " + editor)
-		return editor, nil
 	}
 
-	return "", fmt.Errorf("you must set the editor with the '-bin-editor' flag or set $EDITOR")
+	return cmd
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				panic(err)
+			}
+			return
+		}
+	}
+
 	flag.Parse()
 
 	args := flag.Args()
@@ -66,9 +76,14 @@ func main() {
 		panic(err)
 	}
 
-	config.BinEditorCmd, err = getBinEditorCmd(config.BinEditorCmd)
-	if err != nil {
-		panic(err)
+	config.BinEditorCmd = getBinEditorCmd(config.BinEditorCmd)
+
+	if schemaPath != "" {
+		fmt.Println("schema: " + schemaPath)
+		config.Schemas, err = schema.Load(schemaPath)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	err = edit.New(config).Edit()